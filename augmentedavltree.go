@@ -0,0 +1,244 @@
+package container
+
+import (
+	"fmt"
+	"sort"
+)
+
+// augEntry is the value stored in the AVLTree of an AugmentedAVLTree,
+// with the aggregate of the subtree rooted at the node
+type augEntry[V, A any] struct {
+	Value V
+	agg A
+}
+
+// AugmentedAVLTree is an AVLTree where every node keeps the aggregate of its subtree
+// under a user-defined associative operation (monoid), such that the aggregate of
+// any key range can be answered in O(log n), e.g. range sum, range min/max.
+type AugmentedAVLTree[K, V, A any] struct {
+	tree *AVLTree[K, augEntry[V,A]]
+	combine func(A,A) A
+	lift func(K,V) A
+}
+
+// NewAugmentedAVLTree creates a new AugmentedAVLTree given a comparator of key type,
+// an associative function to combine two aggregates (not necessarily commutative,
+// the left operand always comes from smaller keys), and a function to lift an entry
+// to an aggregate
+func NewAugmentedAVLTree[K, V, A any](cmp func(K,K) int, combine func(A,A) A, lift func(K,V) A) *AugmentedAVLTree[K,V,A] {
+	t := &AugmentedAVLTree[K,V,A]{
+		tree: NewAVLTree[K, augEntry[V,A]](cmp),
+		combine: combine,
+		lift: lift,
+	}
+	t.tree.augment = t.augment
+	return t
+}
+
+// augment recomputes the aggregate of node from its children
+func (t *AugmentedAVLTree[K,V,A]) augment(node *avlNode[K, augEntry[V,A]]) {
+	agg := t.lift(node.Key, node.Value.Value)
+	if node.Left != nil {
+		agg = t.combine(node.Left.Value.agg, agg)
+	}
+	if node.Right != nil {
+		agg = t.combine(agg, node.Right.Value.agg)
+	}
+	node.Value.agg = agg
+}
+
+// Has key in the AugmentedAVLTree
+func (t *AugmentedAVLTree[K,V,A]) Has(key K) bool {
+	return t.tree.Has(key)
+}
+
+// Get key from AugmentedAVLTree, return value and whether the key is found
+// if not found, return value is the zero-value of type V
+func (t *AugmentedAVLTree[K,V,A]) Get(key K) (V, bool) {
+	entry, ok := t.tree.Get(key)
+	return entry.Value, ok
+}
+
+// Insert a key-value pair into the AugmentedAVLTree
+func (t *AugmentedAVLTree[K,V,A]) Insert(key K, value V) {
+	t.tree.Insert(key, augEntry[V,A]{Value: value})
+}
+
+// Remove the node with given key in the AugmentedAVLTree if exists
+// it has no-op if key is not in the tree
+func (t *AugmentedAVLTree[K,V,A]) Remove(key K) {
+	t.tree.Remove(key)
+}
+
+// Len return the size of the AugmentedAVLTree
+func (t *AugmentedAVLTree[K,V,A]) Len() int {
+	return t.tree.Len()
+}
+
+// Clear all element in the AugmentedAVLTree
+func (t *AugmentedAVLTree[K,V,A]) Clear() {
+	t.tree.Clear()
+}
+
+// Validate checks the structure of the AugmentedAVLTree, see AVLTree.Validate for details.
+// Aggregates are not checked since they cannot be compared in general.
+func (t *AugmentedAVLTree[K,V,A]) Validate() error {
+	return t.tree.Validate()
+}
+
+// AggregateAll returns the aggregate of all entries and if the AugmentedAVLTree is not empty
+func (t *AugmentedAVLTree[K,V,A]) AggregateAll() (rAgg A, ok bool) {
+	if t.tree.root != nil {
+		rAgg, ok = t.tree.root.Value.agg, true
+	}
+	return
+}
+
+// Aggregate returns the aggregate of entries with key between lo and hi (both inclusive)
+// in ascending order of keys, and if there is any entry in the range
+func (t *AugmentedAVLTree[K,V,A]) Aggregate(lo, hi K) (rAgg A, ok bool) {
+	cmp := t.tree.cmp
+	// find the highest node in range, whose left subtree contains the lower bound
+	// and right subtree contains the upper bound
+	node := t.tree.root
+	for node != nil {
+		if cmp(node.Key, lo) < 0 {
+			node = node.Right
+		} else if cmp(node.Key, hi) > 0 {
+			node = node.Left
+		} else {
+			break
+		}
+	}
+	if node == nil {
+		return
+	}
+
+	rAgg, ok = t.lift(node.Key, node.Value.Value), true
+	if left, found := t.aggregateFrom(node.Left, lo); found {
+		rAgg = t.combine(left, rAgg)
+	}
+	if right, found := t.aggregateTo(node.Right, hi); found {
+		rAgg = t.combine(rAgg, right)
+	}
+	return
+}
+
+// aggregate of keys greater than or equal to lo in the subtree
+func (t *AugmentedAVLTree[K,V,A]) aggregateFrom(node *avlNode[K, augEntry[V,A]], lo K) (rAgg A, ok bool) {
+	for node != nil {
+		if t.tree.cmp(node.Key, lo) < 0 {
+			node = node.Right
+			continue
+		}
+		part := t.lift(node.Key, node.Value.Value)
+		if node.Right != nil {
+			part = t.combine(part, node.Right.Value.agg)
+		}
+		if ok { // keys visited later are smaller
+			rAgg = t.combine(part, rAgg)
+		} else {
+			rAgg, ok = part, true
+		}
+		node = node.Left
+	}
+	return
+}
+
+// aggregate of keys less than or equal to hi in the subtree
+func (t *AugmentedAVLTree[K,V,A]) aggregateTo(node *avlNode[K, augEntry[V,A]], hi K) (rAgg A, ok bool) {
+	for node != nil {
+		if t.tree.cmp(node.Key, hi) > 0 {
+			node = node.Left
+			continue
+		}
+		part := t.lift(node.Key, node.Value.Value)
+		if node.Left != nil {
+			part = t.combine(node.Left.Value.agg, part)
+		}
+		if ok { // keys visited later are greater
+			rAgg = t.combine(rAgg, part)
+		} else {
+			rAgg, ok = part, true
+		}
+		node = node.Right
+	}
+	return
+}
+
+/////////////////////////////
+///////// Testing ///////////
+/////////////////////////////
+
+func checkAugmentedAVLTree(t *AugmentedAVLTree[int, int, string], keys []int) {
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+	// aggregate is the concatenation of keys, such that the order is checked as well
+	for lo := -1; lo <= 21; lo++ {
+		for hi := lo - 1; hi <= 21; hi++ {
+			expect, expOk := "", false
+			for _, k := range keys {
+				if lo <= k && k <= hi {
+					expect, expOk = expect+fmt.Sprintf("%d,", k), true
+				}
+			}
+			if got, ok := t.Aggregate(lo, hi); got != expect || ok != expOk {
+				panic(fmt.Sprintf("Aggregate(%d,%d): expect %q,%t, but got %q,%t", lo, hi, expect, expOk, got, ok))
+			}
+		}
+	}
+}
+
+func testAugmentedAVLTree() {
+	t := NewAugmentedAVLTree[int, int, string](
+		CmpLess[int],
+		func(x, y string) string { return x + y },
+		func(k, v int) string { return fmt.Sprintf("%d,", k) },
+	)
+	checkAugmentedAVLTree(t, []int{})
+	if _, ok := t.AggregateAll(); ok {
+		panic("Expect AggregateAll on empty tree to fail")
+	}
+
+	keys := []int{}
+	for _, k := range []int{10, 4, 16, 2, 8, 0, 20, 14, 6, 12, 18} {
+		t.Insert(k, k)
+		keys = append(keys, k)
+		sort.Ints(keys)
+		checkAugmentedAVLTree(t, keys)
+	}
+	for _, k := range []int{10, 0, 20, 7} {
+		t.Remove(k)
+		for i := range keys {
+			if keys[i] == k {
+				keys = append(keys[:i], keys[i+1:]...)
+				break
+			}
+		}
+		checkAugmentedAVLTree(t, keys)
+	}
+
+	// range sum and the aggregate is maintained on overwrite
+	sum := NewAugmentedAVLTree[int, int, int](
+		CmpLess[int],
+		func(x, y int) int { return x + y },
+		func(k, v int) int { return v },
+	)
+	for i := 1; i <= 100; i++ {
+		sum.Insert(i, i)
+	}
+	if got, _ := sum.Aggregate(1, 100); got != 5050 {
+		panic(fmt.Sprintf("Expect range sum 5050, but got %d", got))
+	}
+	sum.Insert(50, 0)
+	if got, _ := sum.Aggregate(41, 60); got != 1010-50 {
+		panic(fmt.Sprintf("Expect range sum %d, but got %d", 1010-50, got))
+	}
+	if got, _ := sum.AggregateAll(); got != 5000 {
+		panic(fmt.Sprintf("Expect total sum 5000, but got %d", got))
+	}
+	if v, ok := sum.Get(50); v != 0 || !ok {
+		panic(fmt.Sprintf("Expect value 0, but got %d,%t", v, ok))
+	}
+}
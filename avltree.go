@@ -1,5 +1,7 @@
 package container
 
+import "fmt"
+import "io"
 import "math"
 import "math/rand"
 import "sort"
@@ -47,10 +49,21 @@ func (node *avlNode[K,V]) Bal() int {
 	return node.Left.Height() - node.Right.Height()
 }
 
+// entry of a avlNode, see bstNode
+func (node *avlNode[K,V]) entry() (K, V) {
+	return node.Key, node.Value
+}
+
+// children of a avlNode, see bstNode
+func (node *avlNode[K,V]) children() (*avlNode[K,V], *avlNode[K,V]) {
+	return node.Left, node.Right
+}
+
 // AVLTree data structure with no duplicated value
 type AVLTree[K, V any] struct {
 	root *avlNode[K,V]
 	cmp func(K,K) int
+	augment func(*avlNode[K,V]) // optional hook to maintain extra data of a node
 }
 
 // NewAVLTree creates a new AVLTree given a comparator of key type
@@ -60,6 +73,35 @@ func NewAVLTree[K,V any](cmp func(K,K) int) *AVLTree[K,V] {
 	}
 }
 
+// NewAVLTreeFromSorted creates a new AVLTree given a comparator of key type and
+// entries sorted by key in linear time. It returns an error if the keys are not
+// strictly increasing (according to cmp), i.e. unsorted or duplicated.
+func NewAVLTreeFromSorted[K,V any](cmp func(K,K) int, pairs []Pair[K,V]) (*AVLTree[K,V], error) {
+	for i := 1; i < len(pairs); i++ {
+		if c := cmp(pairs[i-1].Key, pairs[i].Key); c == 0 {
+			return nil, fmt.Errorf("duplicated key %v at index %d", pairs[i].Key, i)
+		} else if c > 0 {
+			return nil, fmt.Errorf("key %v at index %d is not sorted", pairs[i].Key, i)
+		}
+	}
+	t := NewAVLTree[K,V](cmp)
+	t.root = buildAVLNodes[K,V](pairs)
+	return t, nil
+}
+
+// NewAVLTreeFromSortedKV is the same as NewAVLTreeFromSorted but the entries are
+// given by keys and values separately. It returns an error if the lengths don't match.
+func NewAVLTreeFromSortedKV[K,V any](cmp func(K,K) int, keys []K, values []V) (*AVLTree[K,V], error) {
+	if len(keys) != len(values) {
+		return nil, fmt.Errorf("length of keys %d and values %d do not match", len(keys), len(values))
+	}
+	pairs := make([]Pair[K,V], len(keys))
+	for i := range keys {
+		pairs[i] = Pair[K,V]{Key: keys[i], Value: values[i]}
+	}
+	return NewAVLTreeFromSorted[K,V](cmp, pairs)
+}
+
 // Has key in the AVLTree
 func (t *AVLTree[K,V]) Has(key K) bool {
 	_, ok := t.Get(key)
@@ -194,79 +236,315 @@ func (t *AVLTree[K,V]) Len() int {
 	return t.root.Size()
 }
 
-// Insert a key-value pair into the AVLTree
-func (t *AVLTree[K,V]) Insert(key K, value V) {
-	t.root = t.insert(t.root, key, value)
+// Rank returns the number of keys strictly less than the given key
+func (t *AVLTree[K,V]) Rank(key K) int {
+	rank, node := 0, t.root
+	for node != nil {
+		if t.cmp(node.Key, key) < 0 {
+			rank += node.Left.Size() + 1
+			node = node.Right
+		} else {
+			node = node.Left
+		}
+	}
+	return rank
 }
 
-// recursively find insert position
-func (t *AVLTree[K,V]) insert(node *avlNode[K,V], key K, value V) *avlNode[K,V] {
-	if node == nil {
-		return &avlNode[K,V]{
-			Key: key,
-			Value: value,
-			size: 1,
-			height: 1,
+// rankInclusive returns the number of keys less than or equal to the given key
+func (t *AVLTree[K,V]) rankInclusive(key K) int {
+	rank, node := 0, t.root
+	for node != nil {
+		if t.cmp(node.Key, key) <= 0 {
+			rank += node.Left.Size() + 1
+			node = node.Right
+		} else {
+			node = node.Left
 		}
 	}
+	return rank
+}
 
-	if cmp := t.cmp(node.Key, key); cmp == 0 {
-		node.Value = value
-		return node
-	} else if cmp > 0 {
-		node.Left = t.insert(node.Left, key, value)
-	} else {
-		node.Right = t.insert(node.Right, key, value)
+// Select returns the i-th (0-indexed) smallest entry (according to cmp) if exists,
+// the entry exists if and only if 0 <= i < t.Len()
+func (t *AVLTree[K,V]) Select(i int) (rKey K, rVal V, ok bool) {
+	if i < 0 || i >= t.Len() {
+		return
 	}
+	node := t.root
+	for node != nil {
+		lsize := node.Left.Size()
+		if i == lsize {
+			rKey, rVal, ok = node.Key, node.Value, true
+			return
+		}
 
-	node.Maintain()
-	return t.fix(node)
+		if i < lsize {
+			node = node.Left
+		} else {
+			i -= lsize + 1
+			node = node.Right
+		}
+	}
+	return
+}
+
+// CountRange returns the number of keys between lo and hi (both inclusive)
+func (t *AVLTree[K,V]) CountRange(lo, hi K) int {
+	if t.cmp(lo, hi) > 0 {
+		return 0
+	}
+	return t.rankInclusive(hi) - t.Rank(lo)
+}
+
+// Each calls fn on each entry in ascending order of keys. It stops once fn returns false.
+func (t *AVLTree[K,V]) Each(fn func(K,V) bool) {
+	t.root.inorder(func(node *avlNode[K,V]) bool {
+		return fn(node.Key, node.Value)
+	})
+}
+
+// Range calls fn on each entry with key between lo and hi in ascending order,
+// whether lo and hi are included is decided by loInclusive and hiInclusive.
+// It stops once fn returns false.
+func (t *AVLTree[K,V]) Range(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool) {
+	rangeNode[*avlNode[K,V],K,V](t.root, t.cmp, lo, hi, loInclusive, hiInclusive, false, fn)
+}
+
+// RangeReverse is the same as Range but visits entries in descending order
+func (t *AVLTree[K,V]) RangeReverse(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool) {
+	rangeNode[*avlNode[K,V],K,V](t.root, t.cmp, lo, hi, loInclusive, hiInclusive, true, fn)
+}
+
+// maxAVLPath is the length of search paths kept on the stack during Insert and Remove,
+// which is enough for trees with billions of entries. Longer paths spill to the heap.
+const maxAVLPath = 64
+
+// Insert a key-value pair into the AVLTree
+func (t *AVLTree[K,V]) Insert(key K, value V) {
+	var buf [maxAVLPath]**avlNode[K,V]
+	path := buf[:0] // links from root to the parent of the insert position
+
+	link := &t.root
+	for *link != nil {
+		node := *link
+		cmp := t.cmp(node.Key, key)
+		if cmp == 0 {
+			node.Value = value
+			if t.augment == nil {
+				return
+			}
+			// the augmented data of the node and its ancestors depends on the value
+			t.rebalance(append(path, link))
+			return
+		}
+
+		path = append(path, link)
+		if cmp > 0 {
+			link = &node.Left
+		} else {
+			link = &node.Right
+		}
+	}
+
+	*link = &avlNode[K,V]{
+		Key: key,
+		Value: value,
+	}
+	t.maintain(*link)
+	t.rebalance(path)
 }
 
 // Remove the node with given key in the AVLTree if exists
 // it has no-op if key is not in the tree
 func (t *AVLTree[K,V]) Remove(key K) {
-	t.root = t.remove(t.root, key)
+	var buf [maxAVLPath]**avlNode[K,V]
+	path := buf[:0] // links from root to the parent of the removed node
+
+	link := &t.root
+	for *link != nil {
+		node := *link
+		cmp := t.cmp(node.Key, key)
+		if cmp == 0 {
+			break
+		}
+
+		path = append(path, link)
+		if cmp > 0 {
+			link = &node.Left
+		} else {
+			link = &node.Right
+		}
+	}
+
+	node := *link
+	if node == nil {
+		return
+	}
+	if node.Left == nil { // right child only or no child
+		*link = node.Right
+	} else if node.Right == nil { // left child only
+		*link = node.Left
+	} else { // both sides have children, continue the descent to the successor
+		path = append(path, link)
+		link = &node.Right
+		for (*link).Left != nil {
+			path = append(path, link)
+			link = &(*link).Left
+		}
+		nxtNode := *link
+		node.Key, node.Value = nxtNode.Key, nxtNode.Value
+		*link = nxtNode.Right
+	}
+	t.rebalance(path)
+}
+
+// maintain the size and height of a node, as well as the augmented data if any
+func (t *AVLTree[K,V]) maintain(node *avlNode[K,V]) {
+	node.Maintain()
+	if t.augment != nil {
+		t.augment(node)
+	}
+}
+
+// maintain and fix the nodes along the path bottom-up
+func (t *AVLTree[K,V]) rebalance(path []**avlNode[K,V]) {
+	for i := len(path) - 1; i >= 0; i-- {
+		node := *path[i]
+		t.maintain(node)
+		*path[i] = t.fix(node)
+	}
+}
+
+// RemoveRange removes all keys between lo and hi (both inclusive) from the AVLTree,
+// the tree is split and joined only once, regardless of the number of removed keys
+func (t *AVLTree[K,V]) RemoveRange(lo, hi K) {
+	if t.cmp(lo, hi) > 0 {
+		return
+	}
+	left, _, rest := t.split(t.root, lo)
+	_, _, right := t.split(rest, hi)
+	t.root = t.concat(left, right)
+}
+
+// RemoveBefore removes all keys strictly less than the given key from the AVLTree
+func (t *AVLTree[K,V]) RemoveBefore(key K) {
+	_, mid, right := t.split(t.root, key)
+	if mid != nil {
+		right = t.join(nil, mid, right)
+	}
+	t.root = right
+}
+
+// RemoveAfter removes all keys strictly greater than the given key from the AVLTree
+func (t *AVLTree[K,V]) RemoveAfter(key K) {
+	left, mid, _ := t.split(t.root, key)
+	if mid != nil {
+		left = t.join(left, mid, nil)
+	}
+	t.root = left
+}
+
+// Split the AVLTree into two AVLTrees with the same comparator, where left contains
+// keys less than the given key and right contains keys greater than or equal to it.
+// All entries are moved to the new trees, such that the AVLTree becomes empty.
+func (t *AVLTree[K,V]) Split(key K) (left, right *AVLTree[K,V]) {
+	left, right = NewAVLTree[K,V](t.cmp), NewAVLTree[K,V](t.cmp)
+	l, mid, r := t.split(t.root, key)
+	if mid != nil {
+		r = t.join(nil, mid, r)
+	}
+	left.root, right.root = l, r
+	t.root = nil
+	return
+}
+
+// Join moves all entries of other into the AVLTree, such that other becomes empty.
+// Keys of other must be all greater than or all less than keys in the AVLTree,
+// otherwise it panics.
+func (t *AVLTree[K,V]) Join(other *AVLTree[K,V]) {
+	if other.root == nil {
+		return
+	}
+	if t.root == nil {
+		t.root, other.root = other.root, nil
+		return
+	}
+
+	tFirst, _, _ := t.GetFirst()
+	tLast, _, _ := t.GetLast()
+	otherFirst, _, _ := other.GetFirst()
+	otherLast, _, _ := other.GetLast()
+	if t.cmp(tLast, otherFirst) < 0 {
+		t.root = t.concat(t.root, other.root)
+	} else if t.cmp(otherLast, tFirst) < 0 {
+		t.root = t.concat(other.root, t.root)
+	} else {
+		panic("Cannot join AVLTrees with overlapping keys")
+	}
+	other.root = nil
 }
 
-// recursively find remove position
-func (t *AVLTree[K,V]) remove(node *avlNode[K,V], key K) *avlNode[K,V] {
+// split the subtree of node into keys less than key, the node with the key
+// (nil if not exists), and keys greater than key
+func (t *AVLTree[K,V]) split(node *avlNode[K,V], key K) (left, mid, right *avlNode[K,V]) {
 	if node == nil {
-		return nil
+		return
 	}
 
 	if cmp := t.cmp(node.Key, key); cmp == 0 {
-		if node.Left == nil && node.Right == nil { // no child
-			return nil
-		}
-		if node.Left == nil { // right child only
-			return node.Right
-		}
-		if node.Right == nil { // left child only
-			return node.Left
-		}
-		// both sides have children
-		nxtNode := t.successor(node)
-		node.Key, node.Value = nxtNode.Key, nxtNode.Value
-		node.Right = t.remove(node.Right, nxtNode.Key)
+		left, mid, right = node.Left, node, node.Right
+		mid.Left, mid.Right = nil, nil
+		t.maintain(mid)
 	} else if cmp > 0 {
-		node.Left = t.remove(node.Left, key)
+		left, mid, right = t.split(node.Left, key)
+		right = t.join(right, node, node.Right)
 	} else {
-		node.Right = t.remove(node.Right, key)
+		left, mid, right = t.split(node.Right, key)
+		left = t.join(node.Left, node, left)
 	}
+	return
+}
 
-	node.Maintain()
-	return t.fix(node)
+// join two balanced subtrees with a middle node, where keys in left are less than
+// the key of mid and keys in right are greater than the key of mid
+func (t *AVLTree[K,V]) join(left, mid, right *avlNode[K,V]) *avlNode[K,V] {
+	if left.Height() > right.Height()+1 { // go down along the right spine of left
+		left.Right = t.join(left.Right, mid, right)
+		t.maintain(left)
+		return t.fix(left)
+	}
+	if right.Height() > left.Height()+1 { // go down along the left spine of right
+		right.Left = t.join(left, mid, right.Left)
+		t.maintain(right)
+		return t.fix(right)
+	}
+	mid.Left, mid.Right = left, right
+	t.maintain(mid)
+	return mid
 }
 
-// find the next greater element
-func (t *AVLTree[K,V]) successor(node *avlNode[K,V]) *avlNode[K,V] {
-	node = node.Right
-	for node.Left != nil {
-		node = node.Left
+// concat two balanced subtrees, where keys in left are less than keys in right
+func (t *AVLTree[K,V]) concat(left, right *avlNode[K,V]) *avlNode[K,V] {
+	if left == nil {
+		return right
 	}
-	return node
-} 
+	if right == nil {
+		return left
+	}
+	right, mid := t.removeMin(right)
+	return t.join(left, mid, right)
+}
+
+// remove the smallest node from the subtree, returns the new subtree and the removed node
+func (t *AVLTree[K,V]) removeMin(node *avlNode[K,V]) (*avlNode[K,V], *avlNode[K,V]) {
+	if node.Left == nil {
+		return node.Right, node
+	}
+	left, minNode := t.removeMin(node.Left)
+	node.Left = left
+	t.maintain(node)
+	return t.fix(node), minNode
+}
 
 // fix the tree to become a balanced binary search tree
 func (t *AVLTree[K,V]) fix(node *avlNode[K,V]) *avlNode[K,V] {
@@ -298,8 +576,8 @@ func (t *AVLTree[K,V]) rotateLeft(node *avlNode[K,V]) *avlNode[K,V] {
 	root := node.Right
 	node.Right = root.Left
 	root.Left = node
-	node.Maintain()
-	root.Maintain()
+	t.maintain(node)
+	t.maintain(root)
 	return root
 }
 
@@ -317,8 +595,8 @@ func (t *AVLTree[K,V]) rotateRight(node *avlNode[K,V]) *avlNode[K,V] {
 	root := node.Left
 	node.Left = root.Right
 	root.Right = node
-	node.Maintain()
-	root.Maintain()
+	t.maintain(node)
+	t.maintain(root)
 	return root
 }
 
@@ -327,6 +605,173 @@ func (t *AVLTree[K,V]) Clear() {
 	t.root = nil
 }
 
+// Validate checks keys are strictly increasing (according to cmp) and every node is
+// balanced with correct size and height, returns an error at the first offending key
+func (t *AVLTree[K,V]) Validate() error {
+	return t.validate(t.root, nil, nil)
+}
+
+// recursively validate the subtree, where keys must be between lo and hi if not nil
+func (t *AVLTree[K,V]) validate(node, lo, hi *avlNode[K,V]) error {
+	if node == nil {
+		return nil
+	}
+
+	if t.cmp(node.Key, node.Key) != 0 {
+		return fmt.Errorf("key %v is not equal to itself", node.Key)
+	}
+	if lo != nil && (t.cmp(lo.Key, node.Key) >= 0 || t.cmp(node.Key, lo.Key) <= 0) {
+		return fmt.Errorf("key %v is not greater than %v", node.Key, lo.Key)
+	}
+	if hi != nil && (t.cmp(node.Key, hi.Key) >= 0 || t.cmp(hi.Key, node.Key) <= 0) {
+		return fmt.Errorf("key %v is not less than %v", node.Key, hi.Key)
+	}
+	if bal := node.Bal(); bal < -1 || bal > 1 {
+		return fmt.Errorf("key %v is not balanced, balance factor is %d", node.Key, bal)
+	}
+	if height := 1 + max(node.Left.Height(), node.Right.Height()); node.height != height {
+		return fmt.Errorf("key %v has height %d, expect %d", node.Key, node.height, height)
+	}
+	if size := 1 + node.Left.Size() + node.Right.Size(); node.size != size {
+		return fmt.Errorf("key %v has size %d, expect %d", node.Key, node.size, size)
+	}
+
+	if err := t.validate(node.Left, lo, node); err != nil {
+		return err
+	}
+	return t.validate(node.Right, node, hi)
+}
+
+// AVLIterator is a cursor over the entries of an AVLTree in the order of keys.
+// An AVLIterator is invalidated once the AVLTree is modified.
+type AVLIterator[K, V any] struct {
+	path []*avlNode[K,V] // nodes from root to the current node
+}
+
+// Begin returns an AVLIterator pointing to the smallest entry of the AVLTree,
+// the iterator is not valid if the AVLTree is empty
+func (t *AVLTree[K,V]) Begin() *AVLIterator[K,V] {
+	it := &AVLIterator[K,V]{}
+	it.pushLeft(t.root)
+	return it
+}
+
+// End returns an AVLIterator pointing to the greatest entry of the AVLTree,
+// the iterator is not valid if the AVLTree is empty
+func (t *AVLTree[K,V]) End() *AVLIterator[K,V] {
+	it := &AVLIterator[K,V]{}
+	it.pushRight(t.root)
+	return it
+}
+
+// Seek returns an AVLIterator pointing to the entry greater than or equal to the given key,
+// the iterator is not valid if such entry doesn't exist
+func (t *AVLTree[K,V]) Seek(key K) *AVLIterator[K,V] {
+	it := &AVLIterator[K,V]{}
+	found := 0 // length of the path to the last visited entry >= key
+	for node := t.root; node != nil; {
+		it.path = append(it.path, node)
+		if t.cmp(node.Key, key) >= 0 {
+			found = len(it.path)
+			node = node.Left
+		} else {
+			node = node.Right
+		}
+	}
+	it.path = it.path[:found]
+	return it
+}
+
+// Valid returns if the AVLIterator points to an entry
+func (it *AVLIterator[K,V]) Valid() bool {
+	return len(it.path) > 0
+}
+
+// Key returns the key of the current entry. The AVLIterator must be valid.
+func (it *AVLIterator[K,V]) Key() K {
+	return it.path[len(it.path)-1].Key
+}
+
+// Value returns the value of the current entry. The AVLIterator must be valid.
+func (it *AVLIterator[K,V]) Value() V {
+	return it.path[len(it.path)-1].Value
+}
+
+// Next moves the AVLIterator to the next greater entry,
+// the iterator becomes invalid if the current entry is the greatest one.
+// The AVLIterator must be valid.
+func (it *AVLIterator[K,V]) Next() {
+	if node := it.path[len(it.path)-1]; node.Right != nil {
+		it.pushLeft(node.Right)
+		return
+	}
+	// go up until we come from a left child
+	for {
+		child := it.path[len(it.path)-1]
+		it.path = it.path[:len(it.path)-1]
+		if len(it.path) == 0 || it.path[len(it.path)-1].Left == child {
+			return
+		}
+	}
+}
+
+// Prev moves the AVLIterator to the next smaller entry,
+// the iterator becomes invalid if the current entry is the smallest one.
+// The AVLIterator must be valid.
+func (it *AVLIterator[K,V]) Prev() {
+	if node := it.path[len(it.path)-1]; node.Left != nil {
+		it.pushRight(node.Left)
+		return
+	}
+	// go up until we come from a right child
+	for {
+		child := it.path[len(it.path)-1]
+		it.path = it.path[:len(it.path)-1]
+		if len(it.path) == 0 || it.path[len(it.path)-1].Right == child {
+			return
+		}
+	}
+}
+
+// push node and its left descendants into the path
+func (it *AVLIterator[K,V]) pushLeft(node *avlNode[K,V]) {
+	for ; node != nil; node = node.Left {
+		it.path = append(it.path, node)
+	}
+}
+
+// push node and its right descendants into the path
+func (it *AVLIterator[K,V]) pushRight(node *avlNode[K,V]) {
+	for ; node != nil; node = node.Right {
+		it.path = append(it.path, node)
+	}
+}
+
+// buildAVLNodes builds a perfectly balanced subtree from entries sorted by key in linear time
+func buildAVLNodes[K,V any](entries []Pair[K,V]) *avlNode[K,V] {
+	if len(entries) == 0 {
+		return nil
+	}
+	mid := len(entries) / 2
+	node := &avlNode[K,V]{
+		Key: entries[mid].Key,
+		Value: entries[mid].Value,
+		Left: buildAVLNodes[K,V](entries[:mid]),
+		Right: buildAVLNodes[K,V](entries[mid+1:]),
+	}
+	node.Maintain()
+	return node
+}
+
+// inorder traverses the subtree of node in the order of keys,
+// it stops and returns false once fn returns false
+func (node *avlNode[K,V]) inorder(fn func(*avlNode[K,V]) bool) bool {
+	if node == nil {
+		return true
+	}
+	return node.Left.inorder(fn) && fn(node) && node.Right.inorder(fn)
+}
+
 func max(x, y int) int {
 	if x > y {
 		return x
@@ -348,6 +793,40 @@ func abs(x int) int {
 	return -x
 }
 
+// renderTree converts the AVLTree to renderNodes labeled with key, height and balance factor
+func (t *AVLTree[K,V]) renderTree(fmtKey func(K) string) *renderNode {
+	fmtKey = formatter[K](fmtKey)
+	var convert func(node *avlNode[K,V]) *renderNode
+	convert = func(node *avlNode[K,V]) *renderNode {
+		if node == nil {
+			return nil
+		}
+		return &renderNode{
+			label: fmt.Sprintf("%s (h=%d, bal=%d)", fmtKey(node.Key), node.Height(), node.Bal()),
+			left: convert(node.Left),
+			right: convert(node.Right),
+		}
+	}
+	return convert(t.root)
+}
+
+// WriteDOT writes the AVLTree as a Graphviz DOT digraph, where keys are formatted
+// by fmtKey (fmt.Sprint if nil) with the height and balance factor of the node
+func (t *AVLTree[K,V]) WriteDOT(w io.Writer, fmtKey func(K) string) error {
+	return writeTreeDOT(w, "AVLTree", t.renderTree(fmtKey))
+}
+
+// Pretty returns a sideways ASCII dump of the AVLTree, where keys are formatted
+// by fmtKey (fmt.Sprint if nil) with the height and balance factor of the node
+func (t *AVLTree[K,V]) Pretty(fmtKey func(K) string) string {
+	return prettyTree(t.renderTree(fmtKey))
+}
+
+// String returns a sideways ASCII dump of the AVLTree
+func (t *AVLTree[K,V]) String() string {
+	return t.Pretty(nil)
+}
+
 /////////////////////////////
 ///////// Testing ///////////
 /////////////////////////////
@@ -395,12 +874,15 @@ func checkInorderSorted(t *AVLTree[int, int]) {
 func testAVLTreeElements(t *AVLTree[int, int], size int) {
 	checkBalBST(t)
 	checkInorderSorted(t)
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
 	if t.Len() != size {
 		panic(fmt.Sprintf("Expect tree size to be %d, but got %d.\n", size, t.Len()))
 	}
 }
 
-func checkAVLTreeElement(key, val int, ok int, expKey, expVal int, expOk bool) {
+func checkAVLTreeElement(key, val int, ok bool, expKey, expVal int, expOk bool) {
 	if key != expKey || val != expVal || ok != expOk {
 		panic(fmt.Sprintf("Expect key, val, ok to be %d,%d,%t, but got %d,%d,%t.\n", key, val, ok, expKey,expVal, expOk))
 	}
@@ -642,3 +1124,359 @@ func testAVLTree() {
 	}
 }
 
+func checkAVLTreeNum(got, expect int) {
+	if got != expect {
+		panic(fmt.Sprintf("answer does not match, got %d, expect %d", got, expect))
+	}
+}
+
+func testAVLTreeRank() {
+	t := NewAVLTree[int, int](CmpLess[int])
+	checkAVLTreeNum(t.Rank(1), 0)
+	_, _, ok := t.Select(0)
+	checkAVLTreeElement(0, 0, ok, 0, 0, false)
+	checkAVLTreeNum(t.CountRange(0, 10), 0)
+
+	// keys are 0, 2, 4, ..., 98
+	for i := 0; i < 50; i++ {
+		t.Insert(2*i, -2*i)
+	}
+	for i := 0; i < 50; i++ {
+		checkAVLTreeNum(t.Rank(2*i), i)
+		checkAVLTreeNum(t.Rank(2*i+1), i+1)
+		key, val, ok := t.Select(i)
+		checkAVLTreeElement(key, val, ok, 2*i, -2*i, true)
+	}
+	checkAVLTreeNum(t.Rank(-1), 0)
+	checkAVLTreeNum(t.Rank(100), 50)
+	_, _, ok = t.Select(-1)
+	checkAVLTreeElement(0, 0, ok, 0, 0, false)
+	_, _, ok = t.Select(50)
+	checkAVLTreeElement(0, 0, ok, 0, 0, false)
+
+	checkAVLTreeNum(t.CountRange(0, 98), 50)
+	checkAVLTreeNum(t.CountRange(-10, 200), 50)
+	checkAVLTreeNum(t.CountRange(10, 20), 6)
+	checkAVLTreeNum(t.CountRange(11, 19), 4)
+	checkAVLTreeNum(t.CountRange(11, 11), 0)
+	checkAVLTreeNum(t.CountRange(12, 12), 1)
+	checkAVLTreeNum(t.CountRange(20, 10), 0)
+
+	// ranks are maintained after removal
+	for i := 0; i < 50; i += 2 {
+		t.Remove(2*i)
+	}
+	for i := 0; i < 25; i++ {
+		checkAVLTreeNum(t.Rank(4*i+2), i)
+		key, val, ok := t.Select(i)
+		checkAVLTreeElement(key, val, ok, 4*i+2, -4*i-2, true)
+	}
+}
+
+func checkAVLIterator(t *AVLTree[int, int], it *AVLIterator[int, int], reverse bool, expect []int) {
+	for _, k := range expect {
+		if !it.Valid() || it.Key() != k || it.Value() != -k {
+			panic(fmt.Sprintf("Expect iterator at key %d", k))
+		}
+		if reverse {
+			it.Prev()
+		} else {
+			it.Next()
+		}
+	}
+	if it.Valid() {
+		panic(fmt.Sprintf("Expect iterator to be invalid, but got key %d", it.Key()))
+	}
+}
+
+func testAVLIterator() {
+	t := NewAVLTree[int, int](CmpLess[int])
+	checkAVLIterator(t, t.Begin(), false, []int{})
+	checkAVLIterator(t, t.End(), true, []int{})
+	checkAVLIterator(t, t.Seek(1), false, []int{})
+
+	keys := []int{}
+	for i := 0; i < 20; i++ {
+		t.Insert(2*i, -2*i)
+		keys = append(keys, 2*i)
+	}
+	reversed := []int{}
+	for i := len(keys) - 1; i >= 0; i-- {
+		reversed = append(reversed, keys[i])
+	}
+	checkAVLIterator(t, t.Begin(), false, keys)
+	checkAVLIterator(t, t.End(), true, reversed)
+	checkAVLIterator(t, t.Seek(-1), false, keys)
+	checkAVLIterator(t, t.Seek(10), false, keys[5:])
+	checkAVLIterator(t, t.Seek(11), false, keys[6:])
+	checkAVLIterator(t, t.Seek(11), true, reversed[len(keys)-7:])
+	checkAVLIterator(t, t.Seek(39), false, []int{})
+
+	// move in both directions
+	it := t.Seek(20)
+	it.Next()
+	it.Prev()
+	it.Prev()
+	checkAVLIterator(t, it, false, keys[9:])
+}
+
+func checkAVLRange(t *AVLTree[int, int], lo, hi int, loInclusive, hiInclusive bool, expect []int) {
+	got := []int{}
+	t.Range(lo, hi, loInclusive, hiInclusive, func(k, v int) bool {
+		got = append(got, k)
+		return true
+	})
+	gotReverse := []int{}
+	t.RangeReverse(lo, hi, loInclusive, hiInclusive, func(k, v int) bool {
+		gotReverse = append(gotReverse, k)
+		return true
+	})
+	if len(got) != len(expect) || len(gotReverse) != len(expect) {
+		panic(fmt.Sprintf("Expect range to have %d keys, but got %v and reversed %v", len(expect), got, gotReverse))
+	}
+	for i := range expect {
+		if got[i] != expect[i] || gotReverse[len(expect)-1-i] != expect[i] {
+			panic(fmt.Sprintf("Expect range keys %v, but got %v and reversed %v", expect, got, gotReverse))
+		}
+	}
+}
+
+func testAVLTreeRange() {
+	t := NewAVLTree[int, int](CmpLess[int])
+	checkAVLRange(t, 0, 10, true, true, []int{})
+
+	for i := 1; i <= 10; i++ {
+		t.Insert(i, 0)
+	}
+	checkAVLRange(t, 3, 6, true, true, []int{3, 4, 5, 6})
+	checkAVLRange(t, 3, 6, false, true, []int{4, 5, 6})
+	checkAVLRange(t, 3, 6, true, false, []int{3, 4, 5})
+	checkAVLRange(t, 3, 6, false, false, []int{4, 5})
+	checkAVLRange(t, 3, 4, false, false, []int{})
+	checkAVLRange(t, 5, 5, true, true, []int{5})
+	checkAVLRange(t, 5, 5, false, true, []int{})
+	checkAVLRange(t, 6, 3, true, true, []int{})
+	checkAVLRange(t, -5, 100, false, false, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+
+	// stop early
+	got := []int{}
+	t.Range(2, 9, true, true, func(k, v int) bool {
+		got = append(got, k)
+		return k < 4
+	})
+	checkAVLTreeNum(len(got), 3)
+	got = got[:0]
+	t.RangeReverse(2, 9, true, true, func(k, v int) bool {
+		got = append(got, k)
+		return k > 8
+	})
+	checkAVLTreeNum(len(got), 2)
+}
+
+func checkAVLTreeKeys(t *AVLTree[int, int], expect []int) {
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+
+	keys := []int{}
+	t.root.inorder(func(node *avlNode[int, int]) bool {
+		keys = append(keys, node.Key)
+		return true
+	})
+	if len(keys) != len(expect) || t.Len() != len(expect) {
+		panic(fmt.Sprintf("Expect tree keys %v, but got %v", expect, keys))
+	}
+	for i := range expect {
+		if keys[i] != expect[i] {
+			panic(fmt.Sprintf("Expect tree keys %v, but got %v", expect, keys))
+		}
+	}
+}
+
+func newAVLTreeOfRange(lo, hi int) (*AVLTree[int, int], []int) {
+	t := NewAVLTree[int, int](CmpLess[int])
+	keys := []int{}
+	for i := lo; i < hi; i++ {
+		t.Insert(i, -i)
+		keys = append(keys, i)
+	}
+	return t, keys
+}
+
+func testAVLTreeRemoveRange() {
+	t, keys := newAVLTreeOfRange(0, 0)
+	t.RemoveRange(0, 10)
+	t.RemoveBefore(5)
+	t.RemoveAfter(5)
+	checkAVLTreeKeys(t, keys)
+
+	t, keys = newAVLTreeOfRange(0, 100)
+	t.RemoveRange(10, 19)
+	checkAVLTreeKeys(t, append(append([]int{}, keys[:10]...), keys[20:]...))
+	t.RemoveRange(15, 30) // bounds are not in the tree
+	checkAVLTreeKeys(t, append(append([]int{}, keys[:10]...), keys[31:]...))
+	t.RemoveRange(50, 40)
+	checkAVLTreeKeys(t, append(append([]int{}, keys[:10]...), keys[31:]...))
+	t.RemoveBefore(5)
+	checkAVLTreeKeys(t, append(append([]int{}, keys[5:10]...), keys[31:]...))
+	t.RemoveBefore(20)
+	checkAVLTreeKeys(t, keys[31:])
+	t.RemoveAfter(80)
+	checkAVLTreeKeys(t, keys[31:81])
+	t.RemoveAfter(90)
+	checkAVLTreeKeys(t, keys[31:81])
+	t.RemoveRange(-10, 60)
+	checkAVLTreeKeys(t, keys[61:81])
+	t.RemoveRange(0, 100)
+	checkAVLTreeKeys(t, []int{})
+
+	// unbalanced remaining parts
+	for i := 0; i < 100; i++ {
+		t, keys = newAVLTreeOfRange(0, 100)
+		lo := rand.Intn(100)
+		hi := lo + rand.Intn(100-lo)
+		t.RemoveRange(lo, hi)
+		checkAVLTreeKeys(t, append(append([]int{}, keys[:lo]...), keys[hi+1:]...))
+
+		// the tree is still usable after removing a range
+		t.Insert(lo, -lo)
+		t.Remove(0)
+		expect := keys[hi+1:]
+		if lo > 0 {
+			expect = append(append(append([]int{}, keys[1:lo]...), lo), expect...)
+		}
+		checkAVLTreeKeys(t, expect)
+	}
+}
+
+func testAVLTreeSplitJoin() {
+	t, keys := newAVLTreeOfRange(0, 0)
+	left, right := t.Split(0)
+	checkAVLTreeKeys(left, keys)
+	checkAVLTreeKeys(right, keys)
+	left.Join(right)
+	checkAVLTreeKeys(left, keys)
+
+	t, keys = newAVLTreeOfRange(0, 100)
+	left, right = t.Split(30)
+	checkAVLTreeKeys(t, []int{})
+	checkAVLTreeKeys(left, keys[:30])
+	checkAVLTreeKeys(right, keys[30:])
+	left.Join(right)
+	checkAVLTreeKeys(left, keys)
+	checkAVLTreeKeys(right, []int{})
+
+	left, right = left.Split(-1)
+	checkAVLTreeKeys(left, []int{})
+	checkAVLTreeKeys(right, keys)
+	left, right = right.Split(1000)
+	checkAVLTreeKeys(left, keys)
+	checkAVLTreeKeys(right, []int{})
+
+	// join in reversed order and trees with different heights
+	for i := 0; i < 100; i++ {
+		t, keys = newAVLTreeOfRange(0, 100)
+		key := rand.Intn(100)
+		left, right = t.Split(key)
+		checkAVLTreeKeys(left, keys[:key])
+		checkAVLTreeKeys(right, keys[key:])
+		right.Join(left)
+		checkAVLTreeKeys(right, keys)
+	}
+
+	// overlapping keys
+	defer func() {
+		if recover() == nil {
+			panic("Expect Join to panic on overlapping keys")
+		}
+	}()
+	left, _ = newAVLTreeOfRange(0, 10)
+	right, _ = newAVLTreeOfRange(5, 15)
+	left.Join(right)
+}
+
+func testAVLTreeFromSorted() {
+	pairs := []Pair[int, int]{}
+	keys, values := []int{}, []int{}
+	for n := 0; n < 70; n++ {
+		t, err := NewAVLTreeFromSorted[int, int](CmpLess[int], pairs)
+		if err != nil {
+			panic(err)
+		}
+		checkAVLTreeKeys(t, keys)
+		t, err = NewAVLTreeFromSortedKV[int, int](CmpLess[int], keys, values)
+		if err != nil {
+			panic(err)
+		}
+		checkAVLTreeKeys(t, keys)
+		for _, k := range keys {
+			val, ok := t.Get(k)
+			checkAVLTreeElement(0, val, ok, 0, -k, true)
+		}
+
+		// the built tree is still usable
+		t.Insert(-1, 1)
+		t.Remove(n / 2)
+		expect := append([]int{-1}, keys...)
+		if pos := sort.SearchInts(expect, n/2); pos < len(expect) && expect[pos] == n/2 {
+			expect = append(expect[:pos], expect[pos+1:]...)
+		}
+		checkAVLTreeKeys(t, expect)
+
+		pairs = append(pairs, Pair[int, int]{Key: 2 * n, Value: -2 * n})
+		keys, values = append(keys, 2*n), append(values, -2*n)
+	}
+
+	if _, err := NewAVLTreeFromSortedKV[int, int](CmpLess[int], []int{1, 2}, []int{1}); err == nil {
+		panic("Expect error on mismatched length")
+	}
+	if _, err := NewAVLTreeFromSortedKV[int, int](CmpLess[int], []int{1, 3, 2}, []int{1, 2, 3}); err == nil {
+		panic("Expect error on unsorted keys")
+	}
+	if _, err := NewAVLTreeFromSortedKV[int, int](CmpLess[int], []int{1, 2, 2}, []int{1, 2, 3}); err == nil {
+		panic("Expect error on duplicated keys")
+	}
+}
+
+func expectAVLTreeInvalid(t *AVLTree[int, int], reason string) {
+	if err := t.Validate(); err == nil {
+		panic(fmt.Sprintf("Expect Validate to fail on %s", reason))
+	}
+}
+
+func testAVLTreeValidate() {
+	t, _ := newAVLTreeOfRange(0, 10)
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+
+	t.root.Left.Key = 100
+	expectAVLTreeInvalid(t, "unsorted keys")
+	t.root.Left.Key = t.root.Key
+	expectAVLTreeInvalid(t, "duplicated keys")
+
+	t, _ = newAVLTreeOfRange(0, 10)
+	t.root.height++
+	expectAVLTreeInvalid(t, "wrong height")
+
+	t, _ = newAVLTreeOfRange(0, 10)
+	t.root.Right.size++
+	expectAVLTreeInvalid(t, "wrong size")
+
+	t, _ = newAVLTreeOfRange(0, 10)
+	t.root.Left = nil
+	t.root.Maintain()
+	expectAVLTreeInvalid(t, "unbalanced nodes")
+
+	// a comparator that is not antisymmetric
+	t = NewAVLTree[int, int](func(x, y int) int {
+		if x == y {
+			return 0
+		}
+		return -1
+	})
+	for i := 0; i < 3; i++ {
+		t.Insert(i, i)
+	}
+	expectAVLTreeInvalid(t, "inconsistent comparator")
+}
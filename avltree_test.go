@@ -0,0 +1,144 @@
+package container
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// insertRecursive is the recursive Insert before the single-descent rewrite,
+// kept as a baseline for BenchmarkAVLTreeInsertRemove
+func (t *AVLTree[K,V]) insertRecursive(node *avlNode[K,V], key K, value V) *avlNode[K,V] {
+	if node == nil {
+		return &avlNode[K,V]{
+			Key: key,
+			Value: value,
+			size: 1,
+			height: 1,
+		}
+	}
+
+	if cmp := t.cmp(node.Key, key); cmp == 0 {
+		node.Value = value
+		return node
+	} else if cmp > 0 {
+		node.Left = t.insertRecursive(node.Left, key, value)
+	} else {
+		node.Right = t.insertRecursive(node.Right, key, value)
+	}
+
+	node.Maintain()
+	return t.fix(node)
+}
+
+// removeRecursive is the recursive Remove before the single-descent rewrite,
+// kept as a baseline for BenchmarkAVLTreeInsertRemove
+func (t *AVLTree[K,V]) removeRecursive(node *avlNode[K,V], key K) *avlNode[K,V] {
+	if node == nil {
+		return nil
+	}
+
+	if cmp := t.cmp(node.Key, key); cmp == 0 {
+		if node.Left == nil && node.Right == nil { // no child
+			return nil
+		}
+		if node.Left == nil { // right child only
+			return node.Right
+		}
+		if node.Right == nil { // left child only
+			return node.Left
+		}
+		// both sides have children
+		nxtNode := node.Right
+		for nxtNode.Left != nil {
+			nxtNode = nxtNode.Left
+		}
+		node.Key, node.Value = nxtNode.Key, nxtNode.Value
+		node.Right = t.removeRecursive(node.Right, nxtNode.Key)
+	} else if cmp > 0 {
+		node.Left = t.removeRecursive(node.Left, key)
+	} else {
+		node.Right = t.removeRecursive(node.Right, key)
+	}
+
+	node.Maintain()
+	return t.fix(node)
+}
+
+// benchOps runs op(i) for i in [0, b.N) with the timer on. If undo is not nil,
+// it is called on each batch of ops with the timer off, such that the container
+// under test keeps its size.
+func benchOps(b *testing.B, op, undo func(i int)) {
+	const batch = 1000
+	for i := 0; i < b.N; i += batch {
+		j := min(i+batch, b.N)
+		for k := i; k < j; k++ {
+			op(k)
+		}
+		if undo != nil {
+			b.StopTimer()
+			for k := i; k < j; k++ {
+				undo(k)
+			}
+			b.StartTimer()
+		}
+	}
+}
+
+// BenchmarkAVLTreeInsertRemove compares the iterative Insert and Remove with the
+// recursive baseline on a tree of 1e5 keys, reporting comparator calls per op.
+// Even keys are in the tree, odd keys are inserted and removed again.
+func BenchmarkAVLTreeInsertRemove(b *testing.B) {
+	const n = 100000
+	perm := rand.New(rand.NewSource(1)).Perm(n)
+
+	for _, recursive := range []bool{false, true} {
+		calls := 0
+		t := NewAVLTree[int, int](func(a, b int) int {
+			calls++
+			return CmpLess[int](a, b)
+		})
+		name, insert, remove := "Iterative", t.Insert, t.Remove
+		if recursive {
+			name = "Recursive"
+			insert = func(key, value int) { t.root = t.insertRecursive(t.root, key, value) }
+			remove = func(key int) { t.root = t.removeRecursive(t.root, key) }
+		}
+		for _, i := range perm {
+			insert(2*i, -2*i)
+		}
+		// restore the tree without counting comparator calls
+		untracked := func(fn func()) {
+			saved := calls
+			fn()
+			calls = saved
+		}
+
+		b.Run(name+"/Insert", func(b *testing.B) {
+			b.ReportAllocs()
+			calls = 0
+			benchOps(b, func(i int) {
+				key := 2*perm[i%n] + 1
+				insert(key, -key)
+			}, func(i int) {
+				untracked(func() { remove(2*perm[i%n] + 1) })
+			})
+			b.ReportMetric(float64(calls)/float64(b.N), "cmps/op")
+		})
+		b.Run(name+"/Remove", func(b *testing.B) {
+			b.ReportAllocs()
+			calls = 0
+			benchOps(b, func(i int) {
+				remove(2 * perm[i%n])
+			}, func(i int) {
+				key := 2 * perm[i%n]
+				untracked(func() { insert(key, -key) })
+			})
+			b.ReportMetric(float64(calls)/float64(b.N), "cmps/op")
+		})
+
+		if err := t.Validate(); err != nil || t.Len() != n {
+			panic(fmt.Sprintf("%s: expect a valid tree of size %d, but got %d, %v", name, n, t.Len(), err))
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package container
+
+// bstNode is the constraint of nodes of binary search trees, such that AVLTree,
+// RBTree and Treap share the code of traversals. The zero value of N is the nil node.
+type bstNode[N, K, V any] interface {
+	comparable
+	entry() (K, V)
+	children() (left, right N)
+}
+
+// eachNode visits the subtree of node in order, returns false if fn asks to stop
+func eachNode[N bstNode[N,K,V], K, V any](node N, fn func(K,V) bool) bool {
+	var null N
+	if node == null {
+		return true
+	}
+	left, right := node.children()
+	key, value := node.entry()
+	return eachNode[N,K,V](left, fn) && fn(key, value) && eachNode[N,K,V](right, fn)
+}
+
+// rangeNode visits the subtrees of node that may contain keys in range (according to cmp)
+// in ascending order, or descending order if reverse. returns false if fn asks to stop
+func rangeNode[N bstNode[N,K,V], K, V any](node N, cmp func(K,K) int, lo, hi K, loInclusive, hiInclusive, reverse bool, fn func(K,V) bool) bool {
+	var null N
+	if node == null {
+		return true
+	}
+
+	key, value := node.entry()
+	cmpLo, cmpHi := cmp(key, lo), cmp(key, hi)
+	first, second := node.children()
+	visitFirst, visitSecond := cmpLo > 0, cmpHi < 0
+	if reverse {
+		first, second = second, first
+		visitFirst, visitSecond = visitSecond, visitFirst
+	}
+
+	if visitFirst && !rangeNode[N,K,V](first, cmp, lo, hi, loInclusive, hiInclusive, reverse, fn) {
+		return false
+	}
+	inRange := (cmpLo > 0 || (loInclusive && cmpLo == 0)) && (cmpHi < 0 || (hiInclusive && cmpHi == 0))
+	if inRange && !fn(key, value) {
+		return false
+	}
+	if visitSecond && !rangeNode[N,K,V](second, cmp, lo, hi, loInclusive, hiInclusive, reverse, fn) {
+		return false
+	}
+	return true
+}
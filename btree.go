@@ -0,0 +1,629 @@
+package container
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// defaultBTreeDegree is the minimum degree of a BTree created by NewBTree
+const defaultBTreeDegree = 32
+
+// btreeNode is the BTree node structure, where children is empty for leaves,
+// otherwise children[i] holds keys between keys[i-1] and keys[i]
+type btreeNode[K, V any] struct {
+	keys []K
+	values []V
+	children []*btreeNode[K,V]
+}
+
+// leaf returns if the btreeNode has no children
+func (node *btreeNode[K,V]) leaf() bool {
+	return len(node.children) == 0
+}
+
+// BTree is an ordered map with no duplicated keys, whose nodes hold many entries
+// in contiguous slices, such that it is more cache-friendly than binary trees on
+// large datasets. With minimum degree d, every node except the root holds between
+// d-1 and 2d-1 entries, and all leaves are at the same depth.
+type BTree[K, V any] struct {
+	root *btreeNode[K,V]
+	cmp func(K,K) int
+	degree int
+	size int
+}
+
+// NewBTree creates a new BTree given a comparator of key type with a default degree
+func NewBTree[K,V any](cmp func(K,K) int) *BTree[K,V] {
+	return NewBTreeWithDegree[K,V](cmp, defaultBTreeDegree)
+}
+
+// NewBTreeWithDegree creates a new BTree given a comparator of key type and
+// the minimum degree, which must be at least 2
+func NewBTreeWithDegree[K,V any](cmp func(K,K) int, degree int) *BTree[K,V] {
+	if degree < 2 {
+		panic(fmt.Sprintf("BTree degree must be at least 2, got %d", degree))
+	}
+	return &BTree[K,V]{
+		cmp: cmp,
+		degree: degree,
+	}
+}
+
+// search returns the index of the first key in node greater than or equal to
+// the given key, and if the key at the index is equal to the given key
+func (t *BTree[K,V]) search(node *btreeNode[K,V], key K) (int, bool) {
+	i := sort.Search(len(node.keys), func(i int) bool {
+		return t.cmp(node.keys[i], key) >= 0
+	})
+	return i, i < len(node.keys) && t.cmp(node.keys[i], key) == 0
+}
+
+// Has key in the BTree
+func (t *BTree[K,V]) Has(key K) bool {
+	_, ok := t.Get(key)
+	return ok
+}
+
+// MustGet returns value of given key if the key exists, otherwise
+// returns zero-value of V
+func (t *BTree[K,V]) MustGet(key K) (rVal V) {
+	if v, ok := t.Get(key); ok {
+		rVal = v
+	}
+	return
+}
+
+// Get key from BTree, return value and whether the key is found
+// if not found, return value is the zero-value of type V
+func (t *BTree[K,V]) Get(key K) (rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		i, found := t.search(node, key)
+		if found {
+			rVal, ok = node.values[i], true
+			return
+		}
+		if node.leaf() {
+			return
+		}
+		node = node.children[i]
+	}
+	return
+}
+
+// GetFloor returns the entry less than or equal to the given key if exists
+func (t *BTree[K,V]) GetFloor(key K) (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		i, found := t.search(node, key)
+		if found {
+			rKey, rVal, ok = node.keys[i], node.values[i], true
+			return
+		}
+		if i > 0 {
+			rKey, rVal, ok = node.keys[i-1], node.values[i-1], true
+		}
+		if node.leaf() {
+			return
+		}
+		node = node.children[i]
+	}
+	return
+}
+
+// GetCeiling returns the entry greater than or equal to the given key if exists
+func (t *BTree[K,V]) GetCeiling(key K) (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		i, found := t.search(node, key)
+		if i < len(node.keys) {
+			rKey, rVal, ok = node.keys[i], node.values[i], true
+		}
+		if found || node.leaf() {
+			return
+		}
+		node = node.children[i]
+	}
+	return
+}
+
+// GetLower returns the entry less than the given key if exists
+func (t *BTree[K,V]) GetLower(key K) (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		i, _ := t.search(node, key)
+		if i > 0 {
+			rKey, rVal, ok = node.keys[i-1], node.values[i-1], true
+		}
+		if node.leaf() {
+			return
+		}
+		node = node.children[i]
+	}
+	return
+}
+
+// GetHigher returns the entry greater than the given key if exists
+func (t *BTree[K,V]) GetHigher(key K) (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		i, found := t.search(node, key)
+		if found {
+			i++
+		}
+		if i < len(node.keys) {
+			rKey, rVal, ok = node.keys[i], node.values[i], true
+		}
+		if node.leaf() {
+			return
+		}
+		node = node.children[i]
+	}
+	return
+}
+
+// GetFirst returns the smallest element (according to cmp) in the BTree if exists
+func (t *BTree[K,V]) GetFirst() (rKey K, rVal V, ok bool) {
+	if t.root == nil {
+		return
+	}
+	node := t.root
+	for !node.leaf() {
+		node = node.children[0]
+	}
+	return node.keys[0], node.values[0], true
+}
+
+// GetLast returns the greatest element (according to cmp) in the BTree if exists
+func (t *BTree[K,V]) GetLast() (rKey K, rVal V, ok bool) {
+	if t.root == nil {
+		return
+	}
+	node := t.root
+	for !node.leaf() {
+		node = node.children[len(node.children)-1]
+	}
+	n := len(node.keys)
+	return node.keys[n-1], node.values[n-1], true
+}
+
+// Len return the size of the BTree
+func (t *BTree[K,V]) Len() int {
+	return t.size
+}
+
+// Each calls fn on each entry in ascending order of keys. It stops once fn returns false.
+func (t *BTree[K,V]) Each(fn func(K,V) bool) {
+	if t.root != nil {
+		t.each(t.root, fn)
+	}
+}
+
+// recursively visit the subtree in order, returns false if fn asks to stop
+func (t *BTree[K,V]) each(node *btreeNode[K,V], fn func(K,V) bool) bool {
+	for i := range node.keys {
+		if !node.leaf() && !t.each(node.children[i], fn) {
+			return false
+		}
+		if !fn(node.keys[i], node.values[i]) {
+			return false
+		}
+	}
+	return node.leaf() || t.each(node.children[len(node.keys)], fn)
+}
+
+// Range calls fn on each entry with key between lo and hi in ascending order,
+// whether lo and hi are included is decided by loInclusive and hiInclusive.
+// It stops once fn returns false.
+func (t *BTree[K,V]) Range(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool) {
+	if t.root != nil {
+		t.rangeNode(t.root, lo, hi, loInclusive, hiInclusive, false, fn)
+	}
+}
+
+// RangeReverse is the same as Range but visits entries in descending order
+func (t *BTree[K,V]) RangeReverse(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool) {
+	if t.root != nil {
+		t.rangeNode(t.root, lo, hi, loInclusive, hiInclusive, true, fn)
+	}
+}
+
+// recursively visit the entries and subtrees that may contain keys in range,
+// returns false if fn asks to stop
+func (t *BTree[K,V]) rangeNode(node *btreeNode[K,V], lo, hi K, loInclusive, hiInclusive, reverse bool, fn func(K,V) bool) bool {
+	n := len(node.keys)
+	start, _ := t.search(node, lo) // the first key >= lo
+	end := sort.Search(n, func(i int) bool { // the first key > hi
+		return t.cmp(node.keys[i], hi) > 0
+	})
+
+	// children[start..end] and keys[start..end-1] may be in range
+	visitChild := func(i int) bool {
+		return node.leaf() || t.rangeNode(node.children[i], lo, hi, loInclusive, hiInclusive, reverse, fn)
+	}
+	visitKey := func(i int) bool {
+		if !loInclusive && t.cmp(node.keys[i], lo) == 0 {
+			return true
+		}
+		if !hiInclusive && t.cmp(node.keys[i], hi) == 0 {
+			return true
+		}
+		return fn(node.keys[i], node.values[i])
+	}
+	if end < start {
+		return true
+	}
+
+	if reverse {
+		for i := end; i >= start; i-- {
+			if !visitChild(i) || (i > start && !visitKey(i-1)) {
+				return false
+			}
+		}
+	} else {
+		for i := start; i <= end; i++ {
+			if !visitChild(i) || (i < end && !visitKey(i)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Insert a key-value pair into the BTree
+func (t *BTree[K,V]) Insert(key K, value V) {
+	if t.root == nil {
+		t.root = &btreeNode[K,V]{keys: []K{key}, values: []V{value}}
+		t.size++
+		return
+	}
+
+	if len(t.root.keys) == 2*t.degree-1 { // split the full root, the tree grows by one level
+		t.root = &btreeNode[K,V]{children: []*btreeNode[K,V]{t.root}}
+		t.splitChild(t.root, 0)
+	}
+
+	// split full nodes on the way down, such that a leaf always has room for the key
+	node := t.root
+	for {
+		i, found := t.search(node, key)
+		if found {
+			node.values[i] = value
+			return
+		}
+		if node.leaf() {
+			node.keys = insertAt[K](node.keys, i, key)
+			node.values = insertAt[V](node.values, i, value)
+			t.size++
+			return
+		}
+
+		if len(node.children[i].keys) == 2*t.degree-1 {
+			t.splitChild(node, i)
+			if cmp := t.cmp(node.keys[i], key); cmp == 0 {
+				node.values[i] = value
+				return
+			} else if cmp < 0 {
+				i++
+			}
+		}
+		node = node.children[i]
+	}
+}
+
+// split the full child i of node into two nodes, moving its middle entry to node
+func (t *BTree[K,V]) splitChild(node *btreeNode[K,V], i int) {
+	child, d := node.children[i], t.degree
+	right := &btreeNode[K,V]{
+		keys: append([]K(nil), child.keys[d:]...),
+		values: append([]V(nil), child.values[d:]...),
+	}
+	if !child.leaf() {
+		right.children = append([]*btreeNode[K,V](nil), child.children[d:]...)
+		child.children = truncate[*btreeNode[K,V]](child.children, d)
+	}
+
+	node.keys = insertAt[K](node.keys, i, child.keys[d-1])
+	node.values = insertAt[V](node.values, i, child.values[d-1])
+	node.children = insertAt[*btreeNode[K,V]](node.children, i+1, right)
+	child.keys = truncate[K](child.keys, d-1)
+	child.values = truncate[V](child.values, d-1)
+}
+
+// Remove the entry with given key in the BTree if exists
+// it has no-op if key is not in the tree
+func (t *BTree[K,V]) Remove(key K) {
+	if t.root == nil {
+		return
+	}
+	if t.remove(t.root, key) {
+		t.size--
+	}
+	if len(t.root.keys) == 0 { // the tree shrinks by one level
+		if t.root.leaf() {
+			t.root = nil
+		} else {
+			t.root = t.root.children[0]
+		}
+	}
+}
+
+// remove the key from the subtree of node, which has at least degree keys unless it is the root.
+// It returns if the key is removed.
+func (t *BTree[K,V]) remove(node *btreeNode[K,V], key K) bool {
+	for {
+		i, found := t.search(node, key)
+		if node.leaf() {
+			if !found {
+				return false
+			}
+			node.keys = removeAt[K](node.keys, i)
+			node.values = removeAt[V](node.values, i)
+			return true
+		}
+
+		if found {
+			if left := node.children[i]; len(left.keys) >= t.degree {
+				// replace the entry by its predecessor, then remove the predecessor
+				pred := left
+				for !pred.leaf() {
+					pred = pred.children[len(pred.children)-1]
+				}
+				n := len(pred.keys)
+				node.keys[i], node.values[i] = pred.keys[n-1], pred.values[n-1]
+				key, node = pred.keys[n-1], left
+				continue
+			}
+			if right := node.children[i+1]; len(right.keys) >= t.degree {
+				// replace the entry by its successor, then remove the successor
+				succ := right
+				for !succ.leaf() {
+					succ = succ.children[0]
+				}
+				node.keys[i], node.values[i] = succ.keys[0], succ.values[0]
+				key, node = succ.keys[0], right
+				continue
+			}
+			// both children are minimal, merge them with the entry and remove from the merged node
+			t.mergeChildren(node, i)
+			node = node.children[i]
+			continue
+		}
+
+		// make sure the child to descend into has at least degree keys
+		if len(node.children[i].keys) < t.degree {
+			i = t.fill(node, i)
+		}
+		node = node.children[i]
+	}
+}
+
+// fill the minimal child i of node by borrowing an entry from a sibling or merging
+// with a sibling, returns the index of the child containing the original keys of child i
+func (t *BTree[K,V]) fill(node *btreeNode[K,V], i int) int {
+	child := node.children[i]
+	if i > 0 && len(node.children[i-1].keys) >= t.degree { // borrow from the left sibling
+		left := node.children[i-1]
+		n := len(left.keys)
+		child.keys = insertAt[K](child.keys, 0, node.keys[i-1])
+		child.values = insertAt[V](child.values, 0, node.values[i-1])
+		node.keys[i-1], node.values[i-1] = left.keys[n-1], left.values[n-1]
+		left.keys = truncate[K](left.keys, n-1)
+		left.values = truncate[V](left.values, n-1)
+		if !left.leaf() {
+			child.children = insertAt[*btreeNode[K,V]](child.children, 0, left.children[n])
+			left.children = truncate[*btreeNode[K,V]](left.children, n)
+		}
+		return i
+	}
+	if i < len(node.keys) && len(node.children[i+1].keys) >= t.degree { // borrow from the right sibling
+		right := node.children[i+1]
+		child.keys = append(child.keys, node.keys[i])
+		child.values = append(child.values, node.values[i])
+		node.keys[i], node.values[i] = right.keys[0], right.values[0]
+		right.keys = removeAt[K](right.keys, 0)
+		right.values = removeAt[V](right.values, 0)
+		if !right.leaf() {
+			child.children = append(child.children, right.children[0])
+			right.children = removeAt[*btreeNode[K,V]](right.children, 0)
+		}
+		return i
+	}
+	if i < len(node.keys) {
+		t.mergeChildren(node, i)
+		return i
+	}
+	t.mergeChildren(node, i-1)
+	return i - 1
+}
+
+// merge child i+1 and the entry i of node into child i
+func (t *BTree[K,V]) mergeChildren(node *btreeNode[K,V], i int) {
+	left, right := node.children[i], node.children[i+1]
+	left.keys = append(append(left.keys, node.keys[i]), right.keys...)
+	left.values = append(append(left.values, node.values[i]), right.values...)
+	left.children = append(left.children, right.children...)
+	node.keys = removeAt[K](node.keys, i)
+	node.values = removeAt[V](node.values, i)
+	node.children = removeAt[*btreeNode[K,V]](node.children, i+1)
+}
+
+// Clear all element in the BTree
+func (t *BTree[K,V]) Clear() {
+	t.root = nil
+	t.size = 0
+}
+
+// Validate checks keys are strictly increasing (according to cmp), node occupancy, leaf
+// depths and sizes are correct, returns an error at the first offending key
+func (t *BTree[K,V]) Validate() error {
+	if t.root == nil {
+		if t.size != 0 {
+			return fmt.Errorf("empty tree has size %d", t.size)
+		}
+		return nil
+	}
+	if len(t.root.keys) == 0 {
+		return fmt.Errorf("root has no keys")
+	}
+
+	size, leafDepth := 0, -1
+	var prev *K
+	var validate func(node *btreeNode[K,V], depth int) error
+	validate = func(node *btreeNode[K,V], depth int) error {
+		n := len(node.keys)
+		if node != t.root && n < t.degree-1 {
+			return fmt.Errorf("node of key %v has %d keys, less than %d", node.keys[0], n, t.degree-1)
+		}
+		if n > 2*t.degree-1 || len(node.values) != n {
+			return fmt.Errorf("node of key %v has %d keys and %d values, expect at most %d", node.keys[0], n, len(node.values), 2*t.degree-1)
+		}
+		if node.leaf() {
+			if leafDepth == -1 {
+				leafDepth = depth
+			} else if leafDepth != depth {
+				return fmt.Errorf("leaf of key %v is at depth %d, expect %d", node.keys[0], depth, leafDepth)
+			}
+		} else if len(node.children) != n+1 {
+			return fmt.Errorf("node of key %v has %d children, expect %d", node.keys[0], len(node.children), n+1)
+		}
+
+		for i := 0; i <= n; i++ {
+			if !node.leaf() {
+				if err := validate(node.children[i], depth+1); err != nil {
+					return err
+				}
+			}
+			if i == n {
+				break
+			}
+			key := node.keys[i]
+			if t.cmp(key, key) != 0 {
+				return fmt.Errorf("key %v is not equal to itself", key)
+			}
+			if prev != nil && (t.cmp(*prev, key) >= 0 || t.cmp(key, *prev) <= 0) {
+				return fmt.Errorf("key %v is not greater than %v", key, *prev)
+			}
+			prev = &node.keys[i]
+			size++
+		}
+		return nil
+	}
+	if err := validate(t.root, 0); err != nil {
+		return err
+	}
+	if size != t.size {
+		return fmt.Errorf("tree has size %d, expect %d", t.size, size)
+	}
+	return nil
+}
+
+// insertAt inserts x at index i of s
+func insertAt[T any](s []T, i int, x T) []T {
+	var zero T
+	s = append(s, zero)
+	copy(s[i+1:], s[i:])
+	s[i] = x
+	return s
+}
+
+// removeAt removes the element at index i of s
+func removeAt[T any](s []T, i int) []T {
+	copy(s[i:], s[i+1:])
+	return truncate[T](s, len(s)-1)
+}
+
+// truncate s to length n, the dropped elements are zeroed to be garbage collected
+func truncate[T any](s []T, n int) []T {
+	var zero T
+	for i := n; i < len(s); i++ {
+		s[i] = zero
+	}
+	return s[:n]
+}
+
+/////////////////////////////
+///////// Testing ///////////
+/////////////////////////////
+
+func checkBTreeKeys(t *BTree[int, int], keys []int) {
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+	if t.Len() != len(keys) {
+		panic(fmt.Sprintf("Expect size %d, but got %d", len(keys), t.Len()))
+	}
+	i := 0
+	t.Each(func(k, v int) bool {
+		if k != keys[i] || v != -k {
+			panic(fmt.Sprintf("Expect entry %d,%d at %d, but got %d,%d", keys[i], -keys[i], i, k, v))
+		}
+		i++
+		return true
+	})
+}
+
+func testBTree() {
+	for _, degree := range []int{2, 3, 8} {
+		t := NewBTreeWithDegree[int, int](CmpLess[int], degree)
+		checkBTreeKeys(t, []int{})
+
+		// splits and merges of nodes, other operations are covered by CheckOrderedMapConformance
+		keys := []int{}
+		for _, k := range rand.Perm(300) {
+			t.Insert(k, -k)
+		}
+		for k := 0; k < 300; k++ {
+			if k%3 == 0 {
+				t.Remove(k)
+			} else {
+				keys = append(keys, k)
+			}
+		}
+		checkBTreeKeys(t, keys)
+		rand.Shuffle(len(keys), func(i, j int) {
+			keys[i], keys[j] = keys[j], keys[i]
+		})
+		for _, k := range keys {
+			t.Remove(k)
+		}
+		checkBTreeKeys(t, []int{})
+	}
+
+	t := NewBTree[int, int](CmpLess[int])
+	t.Insert(1, -1)
+	t.Clear()
+	checkBTreeKeys(t, []int{})
+	func() {
+		defer func() {
+			if recover() == nil {
+				panic("Expect NewBTreeWithDegree to panic on degree less than 2")
+			}
+		}()
+		NewBTreeWithDegree[int, int](CmpLess[int], 1)
+	}()
+}
+
+func testBTreeValidate() {
+	t := NewBTreeWithDegree[int, int](CmpLess[int], 2)
+	for i := 0; i < 20; i++ {
+		t.Insert(i, -i)
+	}
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+	leaf := t.root
+	for !leaf.leaf() {
+		leaf = leaf.children[0]
+	}
+	leaf.keys[0] = 100
+	if t.Validate() == nil {
+		panic("Expect Validate to fail on unsorted keys")
+	}
+	leaf.keys[0] = 0
+	t.size++
+	if t.Validate() == nil {
+		panic("Expect Validate to fail on wrong size")
+	}
+}
@@ -0,0 +1,84 @@
+package container
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// BenchmarkBTreeVsAVLTree compares Insert, Get, Remove and Range of 100 keys between BTree
+// and AVLTree holding 1e4 to 1e7 keys, where 1e7 is skipped in short mode since its trees
+// take a while to build. Even keys are in the trees, odd keys are inserted and removed again.
+func BenchmarkBTreeVsAVLTree(b *testing.B) {
+	constructors := []struct {
+		name string
+		newMap func() OrderedMapInterface[int, int]
+	}{
+		{"BTree", func() OrderedMapInterface[int, int] { return NewBTree[int, int](CmpLess[int]) }},
+		{"AVLTree", func() OrderedMapInterface[int, int] { return NewAVLTree[int, int](CmpLess[int]) }},
+	}
+
+	for _, n := range []int{1e4, 1e5, 1e6, 1e7} {
+		if n > 1e6 && testing.Short() {
+			continue
+		}
+		r := rand.New(rand.NewSource(1))
+		perm := r.Perm(n)
+		starts := make([]int, n) // first keys of ranges
+		for i := range starts {
+			starts[i] = 2 * r.Intn(n-100)
+		}
+
+		for _, c := range constructors {
+			m := c.newMap()
+			for _, i := range perm {
+				m.Insert(2*i, -2*i)
+			}
+			prefix := fmt.Sprintf("%d/%s/", n, c.name)
+
+			b.Run(prefix+"Insert", func(b *testing.B) {
+				b.ReportAllocs()
+				benchOps(b, func(i int) {
+					key := 2*perm[i%n] + 1
+					m.Insert(key, -key)
+				}, func(i int) {
+					m.Remove(2*perm[i%n] + 1)
+				})
+			})
+			b.Run(prefix+"Get", func(b *testing.B) {
+				b.ReportAllocs()
+				benchOps(b, func(i int) {
+					if _, ok := m.Get(2 * perm[i%n]); !ok {
+						panic(fmt.Sprintf("Get(%d): expect found", 2*perm[i%n]))
+					}
+				}, nil)
+			})
+			b.Run(prefix+"Remove", func(b *testing.B) {
+				b.ReportAllocs()
+				benchOps(b, func(i int) {
+					m.Remove(2 * perm[i%n])
+				}, func(i int) {
+					key := 2 * perm[i%n]
+					m.Insert(key, -key)
+				})
+			})
+			b.Run(prefix+"Range", func(b *testing.B) {
+				b.ReportAllocs()
+				benchOps(b, func(i int) {
+					count, lo := 0, starts[i%n]
+					m.Range(lo, lo+200, true, false, func(k, v int) bool {
+						count++
+						return true
+					})
+					if count != 100 {
+						panic(fmt.Sprintf("Range(%d,%d): expect 100 keys, but got %d", lo, lo+200, count))
+					}
+				}, nil)
+			})
+
+			if m.Len() != n {
+				panic(fmt.Sprintf("%s: expect size %d, but got %d", prefix, n, m.Len()))
+			}
+		}
+	}
+}
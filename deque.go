@@ -1,14 +1,15 @@
 package container
 
-// Deque is an implementation of double ended queue
+// Deque is an implementation of double ended queue.
+// The zero value is an empty Deque ready to use.
+// A Deque must not be copied after first use.
 type Deque[T any] struct{
-	list *List[T]
+	list List[T]
 }
 
 // NewDeque returns a new Deque object
 func NewDeque[T any]() *Deque[T] {
-	l := NewList[T]()
-	return &Deque[T]{l}
+	return &Deque[T]{}
 }
 
 // PushBack adds an element at the tail of the Deque
@@ -113,4 +114,18 @@ func testDeque() {
 
 	d.Clear()
 	checkDequeSize(d, 0)
-}
\ No newline at end of file
+}
+
+func testDequeZeroValue() {
+	var holder struct {
+		d Deque[int]
+	}
+	d := &holder.d
+	checkDequeSize(d, 0)
+	d.Clear()
+	d.PushFront(1)
+	d.PushBack(2)
+	checkDequeSize(d, 2)
+	checkDequeNum(d.PopFront(), 1)
+	checkDequeNum(d.Back(), 2)
+}
@@ -0,0 +1,305 @@
+package container
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ImmutableAVLTree is a persistent AVLTree, where Insert and Remove return a new tree
+// sharing unchanged nodes with the old one (path copying). A tree is never modified
+// once created, so it is safe to be read by multiple goroutines without locks.
+type ImmutableAVLTree[K, V any] struct {
+	tree AVLTree[K,V] // only read-only methods are called on it
+}
+
+// NewImmutableAVLTree creates a new empty ImmutableAVLTree given a comparator of key type
+func NewImmutableAVLTree[K,V any](cmp func(K,K) int) *ImmutableAVLTree[K,V] {
+	return &ImmutableAVLTree[K,V]{
+		tree: AVLTree[K,V]{cmp: cmp},
+	}
+}
+
+// Has key in the ImmutableAVLTree
+func (t *ImmutableAVLTree[K,V]) Has(key K) bool {
+	return t.tree.Has(key)
+}
+
+// MustGet returns value of given key if the key exists, otherwise
+// returns zero-value of V
+func (t *ImmutableAVLTree[K,V]) MustGet(key K) V {
+	return t.tree.MustGet(key)
+}
+
+// Get key from ImmutableAVLTree, return value and whether the key is found
+// if not found, return value is the zero-value of type V
+func (t *ImmutableAVLTree[K,V]) Get(key K) (V, bool) {
+	return t.tree.Get(key)
+}
+
+// GetFloor returns the entry less than or equal to the given key if exists
+func (t *ImmutableAVLTree[K,V]) GetFloor(key K) (K, V, bool) {
+	return t.tree.GetFloor(key)
+}
+
+// GetCeiling returns the entry greater than or equal to the given key if exists
+func (t *ImmutableAVLTree[K,V]) GetCeiling(key K) (K, V, bool) {
+	return t.tree.GetCeiling(key)
+}
+
+// GetLower returns the entry less than the given key if exists
+func (t *ImmutableAVLTree[K,V]) GetLower(key K) (K, V, bool) {
+	return t.tree.GetLower(key)
+}
+
+// GetHigher returns the entry greater than the given key if exists
+func (t *ImmutableAVLTree[K,V]) GetHigher(key K) (K, V, bool) {
+	return t.tree.GetHigher(key)
+}
+
+// GetFirst returns the smallest element (according to cmp) in the ImmutableAVLTree if exists
+func (t *ImmutableAVLTree[K,V]) GetFirst() (K, V, bool) {
+	return t.tree.GetFirst()
+}
+
+// GetLast returns the greatest element (according to cmp) in the ImmutableAVLTree if exists
+func (t *ImmutableAVLTree[K,V]) GetLast() (K, V, bool) {
+	return t.tree.GetLast()
+}
+
+// Len return the size of the ImmutableAVLTree
+func (t *ImmutableAVLTree[K,V]) Len() int {
+	return t.tree.Len()
+}
+
+// Rank returns the number of keys strictly less than the given key
+func (t *ImmutableAVLTree[K,V]) Rank(key K) int {
+	return t.tree.Rank(key)
+}
+
+// Select returns the i-th (0-indexed) smallest entry (according to cmp) if exists
+func (t *ImmutableAVLTree[K,V]) Select(i int) (K, V, bool) {
+	return t.tree.Select(i)
+}
+
+// CountRange returns the number of keys between lo and hi (both inclusive)
+func (t *ImmutableAVLTree[K,V]) CountRange(lo, hi K) int {
+	return t.tree.CountRange(lo, hi)
+}
+
+// Range calls fn on each entry with key between lo and hi in ascending order,
+// see AVLTree.Range for details
+func (t *ImmutableAVLTree[K,V]) Range(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool) {
+	t.tree.Range(lo, hi, loInclusive, hiInclusive, fn)
+}
+
+// RangeReverse is the same as Range but visits entries in descending order
+func (t *ImmutableAVLTree[K,V]) RangeReverse(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool) {
+	t.tree.RangeReverse(lo, hi, loInclusive, hiInclusive, fn)
+}
+
+// Begin returns an AVLIterator pointing to the smallest entry of the ImmutableAVLTree.
+// The iterator is never invalidated since the tree is never modified.
+func (t *ImmutableAVLTree[K,V]) Begin() *AVLIterator[K,V] {
+	return t.tree.Begin()
+}
+
+// End returns an AVLIterator pointing to the greatest entry of the ImmutableAVLTree
+func (t *ImmutableAVLTree[K,V]) End() *AVLIterator[K,V] {
+	return t.tree.End()
+}
+
+// Seek returns an AVLIterator pointing to the entry greater than or equal to the given key
+func (t *ImmutableAVLTree[K,V]) Seek(key K) *AVLIterator[K,V] {
+	return t.tree.Seek(key)
+}
+
+// Validate checks the structure of the ImmutableAVLTree, see AVLTree.Validate for details
+func (t *ImmutableAVLTree[K,V]) Validate() error {
+	return t.tree.Validate()
+}
+
+// Insert returns a new ImmutableAVLTree with the key-value pair inserted,
+// the ImmutableAVLTree itself is unchanged
+func (t *ImmutableAVLTree[K,V]) Insert(key K, value V) *ImmutableAVLTree[K,V] {
+	return t.withRoot(t.insert(t.tree.root, key, value))
+}
+
+// Remove returns a new ImmutableAVLTree without the given key,
+// the ImmutableAVLTree itself is unchanged. It returns t if the key is not in the tree.
+func (t *ImmutableAVLTree[K,V]) Remove(key K) *ImmutableAVLTree[K,V] {
+	if !t.Has(key) {
+		return t
+	}
+	return t.withRoot(t.remove(t.tree.root, key))
+}
+
+// returns a new ImmutableAVLTree with the same comparator
+func (t *ImmutableAVLTree[K,V]) withRoot(root *avlNode[K,V]) *ImmutableAVLTree[K,V] {
+	return &ImmutableAVLTree[K,V]{
+		tree: AVLTree[K,V]{root: root, cmp: t.tree.cmp},
+	}
+}
+
+// recursively find insert position, copying the nodes on the path
+func (t *ImmutableAVLTree[K,V]) insert(node *avlNode[K,V], key K, value V) *avlNode[K,V] {
+	if node == nil {
+		return &avlNode[K,V]{
+			Key: key,
+			Value: value,
+			size: 1,
+			height: 1,
+		}
+	}
+
+	node = node.clone()
+	if cmp := t.tree.cmp(node.Key, key); cmp == 0 {
+		node.Value = value
+		return node
+	} else if cmp > 0 {
+		node.Left = t.insert(node.Left, key, value)
+	} else {
+		node.Right = t.insert(node.Right, key, value)
+	}
+
+	node.Maintain()
+	return t.fix(node)
+}
+
+// recursively find remove position, copying the nodes on the path.
+// The key must be in the subtree.
+func (t *ImmutableAVLTree[K,V]) remove(node *avlNode[K,V], key K) *avlNode[K,V] {
+	if cmp := t.tree.cmp(node.Key, key); cmp == 0 {
+		if node.Left == nil { // right child only or no child
+			return node.Right
+		}
+		if node.Right == nil { // left child only
+			return node.Left
+		}
+		// both sides have children
+		node = node.clone()
+		right, nxtNode := t.removeMin(node.Right)
+		node.Key, node.Value, node.Right = nxtNode.Key, nxtNode.Value, right
+	} else if cmp > 0 {
+		node = node.clone()
+		node.Left = t.remove(node.Left, key)
+	} else {
+		node = node.clone()
+		node.Right = t.remove(node.Right, key)
+	}
+
+	node.Maintain()
+	return t.fix(node)
+}
+
+// remove the smallest node from the subtree, returns the new subtree and the removed node
+func (t *ImmutableAVLTree[K,V]) removeMin(node *avlNode[K,V]) (*avlNode[K,V], *avlNode[K,V]) {
+	if node.Left == nil {
+		return node.Right, node
+	}
+	node = node.clone()
+	left, minNode := t.removeMin(node.Left)
+	node.Left = left
+	node.Maintain()
+	return t.fix(node), minNode
+}
+
+// fix a copied node by AVLTree's rotations, where the children to be rotated are
+// copied first so that nodes shared with other trees are untouched
+func (t *ImmutableAVLTree[K,V]) fix(node *avlNode[K,V]) *avlNode[K,V] {
+	if bal := node.Bal(); bal > 1 {
+		node.Left = node.Left.clone()
+		if node.Left.Bal() < 0 {
+			node.Left.Right = node.Left.Right.clone()
+		}
+	} else if bal < -1 {
+		node.Right = node.Right.clone()
+		if node.Right.Bal() > 0 {
+			node.Right.Left = node.Right.Left.clone()
+		}
+	}
+	return t.tree.fix(node)
+}
+
+// clone returns a shallow copy of the avlNode
+func (node *avlNode[K,V]) clone() *avlNode[K,V] {
+	c := *node
+	return &c
+}
+
+/////////////////////////////
+///////// Testing ///////////
+/////////////////////////////
+
+func collectImmutableAVLTree(t *ImmutableAVLTree[int, int]) []int {
+	keys := []int{}
+	for it := t.Begin(); it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	return keys
+}
+
+func checkImmutableAVLTree(t *ImmutableAVLTree[int, int], expect []int) {
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+	keys := collectImmutableAVLTree(t)
+	if len(keys) != len(expect) {
+		panic(fmt.Sprintf("Expect tree keys %v, but got %v", expect, keys))
+	}
+	for i := range keys {
+		if keys[i] != expect[i] {
+			panic(fmt.Sprintf("Expect tree keys %v, but got %v", expect, keys))
+		}
+	}
+	for _, k := range expect {
+		if v, ok := t.Get(k); !ok || v != -k {
+			panic(fmt.Sprintf("Expect key %d with value %d, but got %d,%t", k, -k, v, ok))
+		}
+	}
+}
+
+func testImmutableAVLTree() {
+	empty := NewImmutableAVLTree[int, int](CmpLess[int])
+	checkImmutableAVLTree(empty, []int{})
+
+	// keep every version and check they are unchanged
+	versions := []*ImmutableAVLTree[int, int]{empty}
+	expects := [][]int{{}}
+	t := empty
+	keys := []int{}
+	for i := 0; i < 50; i++ {
+		k := (i * 37) % 50
+		t = t.Insert(k, -k)
+		keys = append(keys, k)
+		sort.Ints(keys)
+		versions = append(versions, t)
+		expects = append(expects, append([]int{}, keys...))
+	}
+	for i := 0; i < 50; i += 3 {
+		t = t.Remove(i)
+		pos := sort.SearchInts(keys, i)
+		keys = append(keys[:pos], keys[pos+1:]...)
+		versions = append(versions, t)
+		expects = append(expects, append([]int{}, keys...))
+	}
+	if t.Remove(0) != t {
+		panic("Expect Remove of a missing key to return the same tree")
+	}
+	for i, v := range versions {
+		checkImmutableAVLTree(v, expects[i])
+	}
+
+	// overwrite does not affect the old version
+	t2 := t.Insert(1, 100)
+	if v, _ := t.Get(1); v != -1 {
+		panic(fmt.Sprintf("Expect old version to keep value -1, but got %d", v))
+	}
+	if v, _ := t2.Get(1); v != 100 {
+		panic(fmt.Sprintf("Expect new version to have value 100, but got %d", v))
+	}
+
+	key, val, ok := t.GetFloor(3)
+	checkAVLTreeElement(key, val, ok, 2, -2, true)
+	key, val, ok = t.GetHigher(47)
+	checkAVLTreeElement(key, val, ok, 49, -49, true)
+}
@@ -0,0 +1,219 @@
+package container
+
+import "fmt"
+
+// Interval is a closed interval [Start, End], where Start must not be greater than End
+type Interval[T any] struct {
+	Start T
+	End T
+}
+
+// IntervalTree stores values keyed by intervals and answers overlapping queries in
+// O(log n + k). It is an AugmentedAVLTree keyed by (Start, End) where every node
+// keeps the maximum End of its subtree.
+type IntervalTree[T, V any] struct {
+	tree *AugmentedAVLTree[Interval[T], V, T]
+	cmp func(T,T) int
+}
+
+// NewIntervalTree creates a new IntervalTree given a comparator of endpoint type
+func NewIntervalTree[T, V any](cmp func(T,T) int) *IntervalTree[T,V] {
+	keyCmp := func(x, y Interval[T]) int {
+		if c := cmp(x.Start, y.Start); c != 0 {
+			return c
+		}
+		return cmp(x.End, y.End)
+	}
+	combine := func(x, y T) T {
+		if cmp(x, y) >= 0 {
+			return x
+		}
+		return y
+	}
+	lift := func(iv Interval[T], v V) T {
+		return iv.End
+	}
+	return &IntervalTree[T,V]{
+		tree: NewAugmentedAVLTree[Interval[T], V, T](keyCmp, combine, lift),
+		cmp: cmp,
+	}
+}
+
+// Has the given interval in the IntervalTree
+func (t *IntervalTree[T,V]) Has(iv Interval[T]) bool {
+	return t.tree.Has(iv)
+}
+
+// Get the value of the given interval, return value and whether the interval is found
+// if not found, return value is the zero-value of type V
+func (t *IntervalTree[T,V]) Get(iv Interval[T]) (V, bool) {
+	return t.tree.Get(iv)
+}
+
+// Insert an interval with its value into the IntervalTree,
+// the value is overwritten if the same interval exists
+func (t *IntervalTree[T,V]) Insert(iv Interval[T], value V) {
+	if t.cmp(iv.Start, iv.End) > 0 {
+		panic(fmt.Sprintf("Invalid interval %v", iv))
+	}
+	t.tree.Insert(iv, value)
+}
+
+// Remove the given interval in the IntervalTree if exists
+// it has no-op if the interval is not in the tree
+func (t *IntervalTree[T,V]) Remove(iv Interval[T]) {
+	t.tree.Remove(iv)
+}
+
+// Overlapping returns values of all intervals overlapping with q (sharing at least
+// one point), in the order of intervals
+func (t *IntervalTree[T,V]) Overlapping(q Interval[T]) []V {
+	values := []V{}
+	t.overlapping(t.tree.tree.root, q, func(node *avlNode[Interval[T], augEntry[V,T]]) bool {
+		values = append(values, node.Value.Value)
+		return true
+	})
+	return values
+}
+
+// Stabbing returns values of all intervals containing the given point, in the order of intervals
+func (t *IntervalTree[T,V]) Stabbing(point T) []V {
+	return t.Overlapping(Interval[T]{Start: point, End: point})
+}
+
+// AnyOverlap returns if any interval overlaps with q
+func (t *IntervalTree[T,V]) AnyOverlap(q Interval[T]) bool {
+	return !t.overlapping(t.tree.tree.root, q, func(node *avlNode[Interval[T], augEntry[V,T]]) bool {
+		return false
+	})
+}
+
+// recursively call fn on nodes overlapping with q in order, skipping subtrees whose
+// maximum End is less than q.Start. It returns false if fn asks to stop
+func (t *IntervalTree[T,V]) overlapping(node *avlNode[Interval[T], augEntry[V,T]], q Interval[T], fn func(*avlNode[Interval[T], augEntry[V,T]]) bool) bool {
+	if node == nil || t.cmp(node.Value.agg, q.Start) < 0 {
+		return true
+	}
+	if !t.overlapping(node.Left, q, fn) {
+		return false
+	}
+	if t.cmp(node.Key.Start, q.End) > 0 { // node and its right subtree start after q
+		return true
+	}
+	if t.cmp(q.Start, node.Key.End) <= 0 && !fn(node) {
+		return false
+	}
+	return t.overlapping(node.Right, q, fn)
+}
+
+// Validate checks the underlying tree (see AVLTree.Validate), Start <= End of every interval
+// and the maximum End kept by every node, returns an error at the first offending interval
+func (t *IntervalTree[T,V]) Validate() error {
+	if err := t.tree.Validate(); err != nil {
+		return err
+	}
+	var err error
+	t.tree.tree.root.inorder(func(node *avlNode[Interval[T], augEntry[V,T]]) bool {
+		maxEnd := node.Key.End
+		for _, child := range []*avlNode[Interval[T], augEntry[V,T]]{node.Left, node.Right} {
+			if child != nil && t.cmp(child.Value.agg, maxEnd) > 0 {
+				maxEnd = child.Value.agg
+			}
+		}
+		if t.cmp(node.Key.Start, node.Key.End) > 0 {
+			err = fmt.Errorf("interval %v has start greater than end", node.Key)
+		} else if t.cmp(node.Value.agg, maxEnd) != 0 {
+			err = fmt.Errorf("interval %v has max end %v, expect %v", node.Key, node.Value.agg, maxEnd)
+		}
+		return err == nil
+	})
+	return err
+}
+
+// Len returns the number of intervals in the IntervalTree
+func (t *IntervalTree[T,V]) Len() int {
+	return t.tree.Len()
+}
+
+// Clear all intervals in the IntervalTree
+func (t *IntervalTree[T,V]) Clear() {
+	t.tree.Clear()
+}
+
+/////////////////////////////
+///////// Testing ///////////
+/////////////////////////////
+
+func checkIntervalTree(t *IntervalTree[int, int], intervals []Interval[int]) {
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+	if t.Len() != len(intervals) {
+		panic(fmt.Sprintf("Expect size %d, but got %d", len(intervals), t.Len()))
+	}
+	for lo := -1; lo <= 31; lo++ {
+		for hi := lo; hi <= 31; hi++ {
+			q := Interval[int]{lo, hi}
+			expect := []int{}
+			for i, iv := range intervals {
+				if iv.Start <= hi && lo <= iv.End {
+					expect = append(expect, i)
+				}
+			}
+			got := t.Overlapping(q)
+			if len(got) != len(expect) || t.AnyOverlap(q) != (len(expect) > 0) {
+				panic(fmt.Sprintf("Overlapping(%v): expect %v, but got %v", q, expect, got))
+			}
+			for i := range expect {
+				if got[i] != expect[i] {
+					panic(fmt.Sprintf("Overlapping(%v): expect %v, but got %v", q, expect, got))
+				}
+			}
+		}
+	}
+}
+
+func testIntervalTree() {
+	t := NewIntervalTree[int, int](CmpLess[int])
+	checkIntervalTree(t, []Interval[int]{})
+
+	// intervals are sorted and the value is the index
+	intervals := []Interval[int]{{0, 3}, {1, 20}, {2, 2}, {5, 8}, {5, 10}, {9, 12}, {15, 15}, {16, 30}, {25, 26}}
+	for _, i := range []int{4, 0, 8, 2, 6, 1, 7, 3, 5} {
+		t.Insert(intervals[i], i)
+	}
+	checkIntervalTree(t, intervals)
+
+	stab := t.Stabbing(10)
+	if len(stab) != 3 || stab[0] != 1 || stab[1] != 4 || stab[2] != 5 {
+		panic(fmt.Sprintf("Stabbing(10): expect [1 4 5], but got %v", stab))
+	}
+
+	t.Remove(intervals[1])
+	t.Remove(Interval[int]{1, 2}) // test no-op
+	intervals = append(intervals[:1], intervals[2:]...)
+	for i := range intervals {
+		t.Insert(intervals[i], i)
+	}
+	checkIntervalTree(t, intervals)
+	if v, ok := t.Get(Interval[int]{9, 12}); !ok || v != 4 {
+		panic(fmt.Sprintf("Expect value 4, but got %d,%t", v, ok))
+	}
+
+	t.Clear()
+	checkIntervalTree(t, []Interval[int]{})
+}
+
+func testIntervalTreeValidate() {
+	t := NewIntervalTree[int, int](CmpLess[int])
+	for i := 0; i < 10; i++ {
+		t.Insert(Interval[int]{i, 2 * i}, i)
+	}
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+	t.tree.tree.root.Value.agg = 0
+	if t.Validate() == nil {
+		panic("Expect Validate to fail on wrong max end")
+	}
+}
@@ -2,70 +2,127 @@ package container
 
 import (
 	"fmt"
+	"io"
+	"math/rand"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 // list is a copy of go's container/list package but with type parameter supported.
-// There is a little difference in implementation where this List's zero value is
-// not ready-to-use; instead, one must call NewList() for initialization
+// As container/list, the zero value of List is an empty list ready to use,
+// the sentinel is lazily initialized on the first insertion.
 
 // Node type inside a list
 type Element[T any] struct {
 	Value T
 	prev *Element[T]
 	next *Element[T]
-	list *List[T]
+	owner *listOwner[T]
+}
+
+// listOwner tells which List the elements pointing to it belong to. Owners form a forest
+// where only the roots point to Lists, such that all elements of a List are moved to
+// another List in O(1) by linking the owner roots rather than updating every element.
+type listOwner[T any] struct {
+	list *List[T] // nil unless it is a root
+	parent *listOwner[T]
+}
+
+// root finds the root of the owner and compresses the path to it
+func (o *listOwner[T]) root() *listOwner[T] {
+	root := o
+	for root.parent != nil {
+		root = root.parent
+	}
+	for o != root {
+		o, o.parent = o.parent, root
+	}
+	return root
+}
+
+// list returns the List the element belongs to, or nil if it is removed or a sentinel.
+// It walks the owner chain, which is short since the shorter list is linked under the
+// longer one on splices, and the path is compressed afterwards.
+func (e *Element[T]) list() *List[T] {
+	if e.owner == nil {
+		return nil
+	}
+	return e.owner.root().list
 }
 
-// Prev return the prev element if exists, otherwise return nil
+// Prev return the prev element if exists, otherwise return nil.
+// It takes O(1) without looking up the List, since only sentinels have no owner.
 func (e *Element[T]) Prev() *Element[T] {
-	if prev := e.prev; e.list != nil && prev != e.list.sentinel {
+	if prev := e.prev; prev != nil && prev.owner != nil {
 		return prev
 	}
 	return nil
 }
 
-// Next return the next element if exists, otherwise return nil
+// Next return the next element if exists, otherwise return nil.
+// It takes O(1) without looking up the List, since only sentinels have no owner.
 func (e *Element[T]) Next() *Element[T] {
-	if next := e.next; e.list != nil && next != e.list.sentinel {
+	if next := e.next; next != nil && next.owner != nil {
 		return next
 	}
 	return nil
 }
 
-// A doubly linked list type
+// A doubly linked list type. The zero value is an empty list ready to use.
+// A List must not be copied after first use.
 type List[T any] struct {
 	sentinel *Element[T]
+	owner *listOwner[T] // the owner root of the elements
 	size int
 }
 
 // New initializes an empty List
 func NewList[T any]() *List[T] {
-	sentinel := &Element[T]{}
-	sentinel.prev = sentinel
-	sentinel.next = sentinel
+	l := &List[T]{}
+	l.lazyInit()
+	return l
+}
 
-	l := &List[T]{
-		sentinel: sentinel,
-		size: 0,
+// lazyInit initializes the sentinel of a zero List
+func (l *List[T]) lazyInit() {
+	if l.sentinel != nil {
+		return
 	}
-	sentinel.list = l
+	l.owner = &listOwner[T]{list: l}
+	sentinel := &Element[T]{} // it has no owner, which tells it apart from the elements
+	sentinel.prev = sentinel
+	sentinel.next = sentinel
+	l.sentinel = sentinel
+}
 
-	return l
+// has returns if mark is an element or the sentinel of the list
+func (l *List[T]) has(mark *Element[T]) bool {
+	return (l.sentinel != nil && mark == l.sentinel) || mark.list() == l
 }
 
 // Back return the last element of this list. Return nil if the list is empty
 func (l *List[T]) Back() *Element[T] {
-	return l.sentinel.Prev()
+	if l.size == 0 {
+		return nil
+	}
+	return l.sentinel.prev
 }
 
 // Front return the first element of this list. Return nil if the list is empty
 func (l *List[T]) Front() *Element[T] {
-	return l.sentinel.Next()
+	if l.size == 0 {
+		return nil
+	}
+	return l.sentinel.next
 }
 
 // Clear all elements in the List.
 func (l *List[T]) Clear() {
+	if l.sentinel == nil {
+		return
+	}
+
 	// Clear pointers pointing to sentinel
 	l.sentinel.next.prev = nil
 	l.sentinel.prev.next = nil
@@ -88,11 +145,66 @@ func (l *List[T]) Find(t T) *Element[T] {
 	return nil
 }
 
+// FindFunc returns the first element satisfying pred, or nil if there is no such element
+func (l *List[T]) FindFunc(pred func(T) bool) *Element[T] {
+	for e := l.Front(); e != nil; e = e.Next() {
+		if pred(e.Value) {
+			return e
+		}
+	}
+	return nil
+}
+
+// FindLast returns the last element satisfying pred, or nil if there is no such element
+func (l *List[T]) FindLast(pred func(T) bool) *Element[T] {
+	for e := l.Back(); e != nil; e = e.Prev() {
+		if pred(e.Value) {
+			return e
+		}
+	}
+	return nil
+}
+
+// FindAll returns all elements satisfying pred in order
+func (l *List[T]) FindAll(pred func(T) bool) []*Element[T] {
+	elems := []*Element[T]{}
+	for e := l.Front(); e != nil; e = e.Next() {
+		if pred(e.Value) {
+			elems = append(elems, e)
+		}
+	}
+	return elems
+}
+
+// RemoveFunc removes all elements satisfying pred and returns the number of removed elements
+func (l *List[T]) RemoveFunc(pred func(T) bool) int {
+	n := 0
+	for e := l.Front(); e != nil; {
+		next := e.Next()
+		if pred(e.Value) {
+			l.Remove(e)
+			n++
+		}
+		e = next
+	}
+	return n
+}
+
+// ListFind returns the first element of l equal to x using ==, or nil if x is not in the list
+func ListFind[T comparable](l *List[T], x T) *Element[T] {
+	for e := l.Front(); e != nil; e = e.Next() {
+		if e.Value == x {
+			return e
+		}
+	}
+	return nil
+}
+
 // InsertAfter inserts a new element with value t after the mark element
 // If mark is not an element of l, the list is not modified.
 // The mark must not be nil.
 func (l *List[T]) InsertAfter(t T, mark *Element[T]) *Element[T] {
-	if mark.list != l {
+	if !l.has(mark) {
 		return nil
 	}
 	e := &Element[T]{Value: t}
@@ -104,7 +216,7 @@ func (l *List[T]) InsertAfter(t T, mark *Element[T]) *Element[T] {
 // If mark is not an element of l, the list is not modified.
 // The mark must not be nil.
 func (l *List[T]) InsertBefore(t T, mark *Element[T]) *Element[T] {
-	if mark.list != l {
+	if !l.has(mark) {
 		return nil
 	}
 	e := &Element[T]{Value: t}
@@ -118,7 +230,7 @@ func (l *List[T]) insertAfter(e, mark *Element[T]) *Element[T] {
 	e.prev = mark
 	e.next.prev = e
 	e.prev.next = e
-	e.list = l
+	e.owner = l.owner
 	l.size++
 
 	return e
@@ -130,7 +242,7 @@ func (l *List[T]) insertBefore(e, mark *Element[T]) *Element[T] {
 	e.prev = mark.prev
 	e.next.prev = e
 	e.prev.next = e
-	e.list = l
+	e.owner = l.owner
 	l.size++
 
 	return e
@@ -145,7 +257,7 @@ func (l *List[T]) Len() int {
 // If e or mark is not an element of l, or e == mark, the list is not modified.
 // The element and mark must not be nil.
 func (l *List[T]) MoveAfter(e, mark *Element[T]) {
-	if e.list != l || mark.list != l || e == mark {
+	if e.list() != l || !l.has(mark) || e == mark {
 		return
 	}
 
@@ -157,7 +269,7 @@ func (l *List[T]) MoveAfter(e, mark *Element[T]) {
 // If e or mark is not an element of l, or e == mark, the list is not modified.
 // The element and mark must not be nil.
 func (l *List[T]) MoveBefore(e, mark *Element[T]) {
-	if e.list != l || mark.list != l || e == mark {
+	if e.list() != l || !l.has(mark) || e == mark {
 		return
 	}
 
@@ -181,42 +293,384 @@ func (l *List[T]) MoveToFront(e *Element[T]) {
 
 // PushBack adds an element with value t at the back of the list
 func (l *List[T]) PushBack(t T) *Element[T] {
+	l.lazyInit()
 	return l.InsertBefore(t, l.sentinel)
 }
 
-// PushBackList adds a list of elements at the back of the list
+// PushBackList adds a copy of the elements of other at the back of the list,
+// other is not modified and can be the list itself
 func (l *List[T]) PushBackList(other *List[T]) {
-	for e := other.Front(); e != nil; e = e.Next() {
+	for i, e := other.Len(), other.Front(); i > 0; i, e = i-1, e.Next() {
 		l.PushBack(e.Value)
 	}
 }
 
 // PushFront adds an element with value t at the front of the list
 func (l *List[T]) PushFront(t T) *Element[T] {
+	l.lazyInit()
 	return l.InsertAfter(t, l.sentinel)
 }
 
-// PushFrontList adds a list of elements at the front of the list
+// PushFrontList adds a copy of the elements of other at the front of the list in the same order,
+// other is not modified and can be the list itself
 func (l *List[T]) PushFrontList(other *List[T]) {
-	for e := other.Front(); e != nil; e = e.Next() {
+	for i, e := other.Len(), other.Back(); i > 0; i, e = i-1, e.Prev() {
 		l.PushFront(e.Value)
 	}
 }
 
+// SpliceBack moves all elements of other to the back of the list, such that other becomes empty.
+// If other is the list itself, the list is not modified.
+// Elements are relinked rather than copied, see SpliceAfter for the complexity.
+func (l *List[T]) SpliceBack(other *List[T]) {
+	l.lazyInit()
+	l.spliceAfter(other, l.sentinel.prev)
+}
+
+// SpliceFront moves all elements of other to the front of the list, such that other becomes empty.
+// If other is the list itself, the list is not modified.
+// Elements are relinked rather than copied, see SpliceAfter for the complexity.
+func (l *List[T]) SpliceFront(other *List[T]) {
+	l.lazyInit()
+	l.spliceAfter(other, l.sentinel)
+}
+
+// SpliceAfter moves all elements of other after the mark element in O(1), such that other
+// becomes empty. If mark is not an element of l, or other is the list itself, the lists
+// are not modified. The mark must not be nil.
+func (l *List[T]) SpliceAfter(mark *Element[T], other *List[T]) {
+	if !l.has(mark) {
+		return
+	}
+	l.spliceAfter(other, mark)
+}
+
+// move all elements of other after mark
+func (l *List[T]) spliceAfter(other *List[T], mark *Element[T]) {
+	if other == l || other.size == 0 {
+		return
+	}
+
+	// link the owner root of the shorter list under the other one
+	if l.size >= other.size {
+		other.owner.list = nil
+		other.owner.parent = l.owner
+	} else {
+		l.owner.list = nil
+		l.owner.parent = other.owner
+		other.owner.list = l
+		l.owner = other.owner
+	}
+
+	first, last := other.sentinel.next, other.sentinel.prev
+	l.size += other.size
+	*other = List[T]{} // other no longer links the elements, it is initialized again lazily
+
+	l.linkAfter(first, last, mark)
+}
+
+// MoveRangeAfter moves elements from first to last (both inclusive) to the position after mark in O(1).
+// If first, last or mark is not an element of l, the list is not modified.
+// The elements must not be nil, first must not come after last, and mark must not be
+// in the range, otherwise the list is corrupted.
+func (l *List[T]) MoveRangeAfter(first, last, mark *Element[T]) {
+	if first.list() != l || last.list() != l || !l.has(mark) {
+		return
+	}
+
+	first.prev.next = last.next
+	last.next.prev = first.prev
+	l.linkAfter(first, last, mark)
+}
+
+// link the chain of elements from first to last after mark
+func (l *List[T]) linkAfter(first, last, mark *Element[T]) {
+	first.prev = mark
+	last.next = mark.next
+	mark.next.prev = last
+	mark.next = first
+}
+
 // Remove remove the element from the list and return the value of the element
 // if list does not contain e, nothing will happen
 func (l *List[T]) Remove(e *Element[T]) T {
-	if e.list == l {
+	if e.list() == l {
 		e.prev.next = e.next
 		e.next.prev = e.prev
 		e.next = nil
 		e.prev = nil
-		e.list = nil
+		e.owner = nil
 		l.size--
 	}
 	return e.Value
 }
 
+// Sort the list by cmp with a stable bottom-up merge sort in O(n log n).
+// Elements are relinked rather than copied, such that they stay valid.
+func (l *List[T]) Sort(cmp func(T,T) int) {
+	if l.size < 2 {
+		return
+	}
+
+	// sort the elements as a nil-terminated singly linked list, then restore prev links
+	head := l.sentinel.next
+	l.sentinel.prev.next = nil
+	for width := 1; width < l.size; width *= 2 {
+		var sorted Element[T]
+		tail := &sorted
+		for p := head; p != nil; {
+			left := p
+			right := cutRun[T](left, width)
+			p = cutRun[T](right, width)
+			tail.next = mergeRuns[T](left, right, cmp)
+			for tail.next != nil {
+				tail = tail.next
+			}
+		}
+		head = sorted.next
+	}
+
+	prev := l.sentinel
+	for e := head; e != nil; e = e.next {
+		e.prev = prev
+		prev = e
+	}
+	prev.next = l.sentinel
+	l.sentinel.prev = prev
+	l.sentinel.next = head
+}
+
+// cutRun detaches the first n elements from the nil-terminated run,
+// returns the rest of the run or nil if there is no more than n elements
+func cutRun[T any](e *Element[T], n int) *Element[T] {
+	for i := 1; e != nil && i < n; i++ {
+		e = e.next
+	}
+	if e == nil {
+		return nil
+	}
+	rest := e.next
+	e.next = nil
+	return rest
+}
+
+// mergeRuns merges two sorted nil-terminated runs, elements of left come first on ties
+func mergeRuns[T any](left, right *Element[T], cmp func(T,T) int) *Element[T] {
+	var merged Element[T]
+	tail := &merged
+	for left != nil && right != nil {
+		if cmp(right.Value, left.Value) < 0 {
+			tail.next, right = right, right.next
+		} else {
+			tail.next, left = left, left.next
+		}
+		tail = tail.next
+	}
+	if left != nil {
+		tail.next = left
+	} else {
+		tail.next = right
+	}
+	return merged.next
+}
+
+// Merge moves all elements of other into the list in O(n + m), where both lists must be
+// sorted by cmp, such that the list is sorted and other becomes empty. It is stable,
+// i.e. elements of the list come first on ties. If other is the list itself,
+// the list is not modified. Elements are relinked rather than copied.
+func (l *List[T]) Merge(other *List[T], cmp func(T,T) int) {
+	if other == l || other.size == 0 {
+		return
+	}
+
+	l.lazyInit()
+	mark := l.sentinel.next
+	for o := other.Front(); o != nil; {
+		for mark != l.sentinel && cmp(o.Value, mark.Value) >= 0 {
+			mark = mark.next
+		}
+		next := o.Next()
+		other.Remove(o)
+		l.insertBefore(o, mark)
+		o = next
+	}
+}
+
+// IsSorted returns if the elements of the list are sorted by cmp
+func (l *List[T]) IsSorted(cmp func(T,T) int) bool {
+	for e := l.Front(); e != nil && e.Next() != nil; e = e.Next() {
+		if cmp(e.Next().Value, e.Value) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Rotate the list to the right by n elements, i.e. the last n elements are moved to the front,
+// the list is rotated to the left if n is negative. Elements are relinked in O(min(k, size-k))
+// for k = n mod size.
+func (l *List[T]) Rotate(n int) {
+	if l.size == 0 {
+		return
+	}
+	n = (n%l.size + l.size) % l.size
+	if n == 0 {
+		return
+	}
+
+	// find the new front, which is the nth element from the back
+	var front *Element[T]
+	if n <= l.size/2 {
+		front = l.sentinel.prev
+		for i := 1; i < n; i++ {
+			front = front.prev
+		}
+	} else {
+		front = l.sentinel.next
+		for i := 0; i < l.size-n; i++ {
+			front = front.next
+		}
+	}
+
+	// take out the sentinel and put it back before the new front
+	l.sentinel.prev.next = l.sentinel.next
+	l.sentinel.next.prev = l.sentinel.prev
+	l.sentinel.prev = front.prev
+	l.sentinel.next = front
+	front.prev.next = l.sentinel
+	front.prev = l.sentinel
+}
+
+// ListRange is a view of a contiguous run of elements in a List.
+// A ListRange is invalidated once the List is modified other than through the ListRange,
+// or another ListRange is created over any of its elements.
+type ListRange[T any] struct {
+	list *List[T]
+	owner *listOwner[T] // the owner of the elements in the run, a child of the List's owner
+	first *Element[T]
+	last *Element[T]
+	size int
+}
+
+// Range returns a view of the elements from from to to (both inclusive) in O(k) for k elements.
+// If from or to is not an element of l, or from comes after to, it returns nil.
+// The elements must not be nil.
+func (l *List[T]) Range(from, to *Element[T]) *ListRange[T] {
+	if from.list() != l || to.list() != l {
+		return nil
+	}
+
+	// the elements still belong to l through the new owner, even if to is not reached
+	owner := &listOwner[T]{parent: l.owner}
+	n := 1
+	for e := from; ; e = e.next {
+		e.owner = owner
+		if e == to {
+			break
+		}
+		if e.next == l.sentinel {
+			return nil
+		}
+		n++
+	}
+	return &ListRange[T]{list: l, owner: owner, first: from, last: to, size: n}
+}
+
+// Front returns the first element of the ListRange
+func (r *ListRange[T]) Front() *Element[T] {
+	return r.first
+}
+
+// Back returns the last element of the ListRange
+func (r *ListRange[T]) Back() *Element[T] {
+	return r.last
+}
+
+// Len returns the number of elements in the ListRange
+func (r *ListRange[T]) Len() int {
+	return r.size
+}
+
+// Each calls fn on each element of the ListRange in order. It stops once fn returns false.
+// fn must not modify the List.
+func (r *ListRange[T]) Each(fn func(e *Element[T]) bool) {
+	for e := r.first; ; e = e.next {
+		if !fn(e) || e == r.last {
+			return
+		}
+	}
+}
+
+// Values returns values of the elements in the ListRange in order
+func (r *ListRange[T]) Values() []T {
+	values := make([]T, 0, r.size)
+	r.Each(func(e *Element[T]) bool {
+		values = append(values, e.Value)
+		return true
+	})
+	return values
+}
+
+// Remove cuts the run of elements out of the List in O(1) and returns them as a new List,
+// to which the ListRange belongs afterwards
+func (r *ListRange[T]) Remove() *List[T] {
+	l := r.list
+	r.first.prev.next = r.last.next
+	r.last.next.prev = r.first.prev
+	l.size -= r.size
+
+	cut := NewList[T]()
+	cut.linkAfter(r.first, r.last, cut.sentinel)
+	cut.size = r.size
+	r.owner.parent = cut.owner // the elements belong to cut
+	r.list = cut
+	return cut
+}
+
+// Reverse the order of the elements in the ListRange in place by relinking them,
+// such that the first element becomes the last one
+func (r *ListRange[T]) Reverse() {
+	before, after := r.first.prev, r.last.next
+	for e := r.first; ; {
+		next := e.next
+		e.prev, e.next = e.next, e.prev
+		if e == r.last {
+			break
+		}
+		e = next
+	}
+	before.next, r.last.prev = r.last, before
+	after.prev, r.first.next = r.first, after
+	r.first, r.last = r.last, r.first
+}
+
+// WriteDOT writes the List as a Graphviz DOT digraph of a chain,
+// where elements are formatted by fmtVal (fmt.Sprint if nil)
+func (l *List[T]) WriteDOT(w io.Writer, fmtVal func(T) string) error {
+	fmtVal = formatter[T](fmtVal)
+	labels := make([]string, 0, l.Len())
+	for e := l.Front(); e != nil; e = e.Next() {
+		labels = append(labels, fmtVal(e.Value))
+	}
+	return writeChainDOT(w, "List", labels)
+}
+
+// Pretty returns the elements of the List in one line, e.g. [1 <-> 2 <-> 3],
+// where elements are formatted by fmtVal (fmt.Sprint if nil)
+func (l *List[T]) Pretty(fmtVal func(T) string) string {
+	fmtVal = formatter[T](fmtVal)
+	labels := make([]string, 0, l.Len())
+	for e := l.Front(); e != nil; e = e.Next() {
+		labels = append(labels, fmtVal(e.Value))
+	}
+	return "[" + strings.Join(labels, " <-> ") + "]"
+}
+
+// String returns the elements of the List in one line
+func (l *List[T]) String() string {
+	return l.Pretty(nil)
+}
+
 /////////////////////////////
 ///////// Testing ///////////
 /////////////////////////////
@@ -232,7 +686,7 @@ func checkData(l *List[int], expect []*Element[int]) {
 
 	node := l.sentinel
 	if len(expect) == 0 {
-		if node.next != node || node.prev != node {
+		if node != nil && (node.next != node || node.prev != node) {
 			panic("Initialization failed")
 		}
 	} else {
@@ -240,11 +694,14 @@ func checkData(l *List[int], expect []*Element[int]) {
 			if node.next != e || e.prev != node {
 				panic("Node connection wrong")
 			}
-			if e.list != l {
+			if e.list() != l {
 				panic("Element's list doesn't match")
 			}
 			node = node.next
 		}
+		if node.next != l.sentinel || l.sentinel.prev != node {
+			panic("Node connection wrong")
+		}
 	}
 }
 
@@ -298,4 +755,321 @@ func testList() {
 
 	l.Clear()
 	checkData(l, []*Element[int]{})
-}
\ No newline at end of file
+}
+
+func testListSplice() {
+	newList := func(values ...int) (*List[int], []*Element[int]) {
+		l := NewList[int]()
+		elems := []*Element[int]{}
+		for _, v := range values {
+			elems = append(elems, l.PushBack(v))
+		}
+		return l, elems
+	}
+
+	l, a := newList(1, 2)
+	other, b := newList(3, 4)
+	l.SpliceBack(other)
+	checkData(l, []*Element[int]{a[0], a[1], b[0], b[1]})
+	checkData(other, []*Element[int]{})
+
+	other, c := newList(5, 6)
+	l.SpliceFront(other)
+	checkData(l, []*Element[int]{c[0], c[1], a[0], a[1], b[0], b[1]})
+	checkData(other, []*Element[int]{})
+
+	other, d := newList(7)
+	l.SpliceAfter(a[0], other)
+	checkData(l, []*Element[int]{c[0], c[1], a[0], d[0], a[1], b[0], b[1]})
+	checkData(other, []*Element[int]{})
+
+	// spliced elements belong to the list
+	l.Remove(d[0])
+	l.MoveToFront(b[1])
+	checkData(l, []*Element[int]{b[1], c[0], c[1], a[0], a[1], b[0]})
+
+	// no-ops
+	l.SpliceBack(l)
+	l.SpliceBack(other)
+	l.SpliceAfter(d[0], other)
+	checkData(l, []*Element[int]{b[1], c[0], c[1], a[0], a[1], b[0]})
+	other, e := newList(8)
+	l.SpliceAfter(e[0], other)
+	checkData(other, []*Element[int]{e[0]})
+
+	// move ranges
+	l.MoveRangeAfter(c[0], a[0], b[0])
+	checkData(l, []*Element[int]{b[1], a[1], b[0], c[0], c[1], a[0]})
+	l.MoveRangeAfter(a[1], b[0], a[0])
+	checkData(l, []*Element[int]{b[1], c[0], c[1], a[0], a[1], b[0]})
+	l.MoveRangeAfter(c[0], c[1], b[1])
+	checkData(l, []*Element[int]{b[1], c[0], c[1], a[0], a[1], b[0]})
+	l.MoveRangeAfter(b[1], b[1], b[0])
+	checkData(l, []*Element[int]{c[0], c[1], a[0], a[1], b[0], b[1]})
+	l.MoveRangeAfter(c[0], c[1], e[0])
+	checkData(l, []*Element[int]{c[0], c[1], a[0], a[1], b[0], b[1]})
+
+	// splices don't update the moved elements, and the emptied list is still usable
+	elems := []*Element[int]{c[0], c[1], a[0], a[1], b[0], b[1]}
+	owners := []*listOwner[int]{}
+	for _, x := range elems {
+		owners = append(owners, x.owner)
+	}
+	short, f := newList(9)
+	short.SpliceBack(l)
+	checkData(short, append([]*Element[int]{f[0]}, elems...))
+	checkData(l, []*Element[int]{})
+	for i, x := range elems {
+		if x.owner != owners[i] {
+			panic("Expect SpliceBack not to update the moved elements")
+		}
+	}
+	g := l.PushBack(10)
+	l.SpliceFront(short)
+	checkData(l, append(append([]*Element[int]{f[0]}, elems...), g))
+	checkData(short, []*Element[int]{})
+	short.PushFront(11)
+	short.MoveToBack(a[0])
+	checkData(l, append(append([]*Element[int]{f[0]}, elems...), g))
+	if short.Len() != 1 || short.Front().Value != 11 {
+		panic("Expect elements of the list not to be moved by the emptied list")
+	}
+
+	// iterating after many splices doesn't walk the owner chains
+	lists := []*List[int]{}
+	for i := 0; i < 1024; i++ {
+		single, _ := newList(i)
+		lists = append(lists, single)
+	}
+	for len(lists) > 1 {
+		merged := []*List[int]{}
+		for i := 0; i < len(lists); i += 2 {
+			lists[i].SpliceBack(lists[i+1])
+			merged = append(merged, lists[i])
+		}
+		lists = merged
+	}
+	l = lists[0]
+	elems, parents := []*Element[int]{}, []*listOwner[int]{}
+	for e := l.Front(); e != nil; e = e.Next() {
+		elems = append(elems, e)
+		parents = append(parents, e.owner.parent)
+	}
+	depth := 0
+	for o := elems[len(elems)-1].owner; o.parent != nil; o = o.parent {
+		depth++
+	}
+	if depth < 2 {
+		panic(fmt.Sprintf("Expect a deep owner chain after the splices, but got %d", depth))
+	}
+	for e, i := l.Back(), len(elems)-1; e != nil; e, i = e.Prev(), i-1 {
+		if e != elems[i] || e.Value != i {
+			panic(fmt.Sprintf("Expect %d at %d after the splices, but got %d", i, i, e.Value))
+		}
+	}
+	for i, e := range elems {
+		if e.owner.parent != parents[i] {
+			panic("Expect Next and Prev not to walk the owner chain")
+		}
+	}
+	last := elems[len(elems)-1]
+	owner := last.owner
+	l.MoveToFront(last)
+	if l.Front() != last || last.owner != l.owner || owner.parent != l.owner {
+		panic("Expect the membership check to find the list and compress the owner chain")
+	}
+
+	// copies keep the order and the list can be copied to itself
+	l, _ = newList(1, 2)
+	l.PushFrontList(l)
+	l.PushBackList(l)
+	values := []int{}
+	for e := l.Front(); e != nil; e = e.Next() {
+		values = append(values, e.Value)
+	}
+	if fmt.Sprint(values) != "[1 2 1 2 1 2 1 2]" {
+		panic(fmt.Sprintf("Expect [1 2 1 2 1 2 1 2], but got %v", values))
+	}
+}
+
+func testListSort() {
+	// sort pairs by key only, such that the stability is checked by values
+	cmp := func(x, y Pair[int, int]) int {
+		return CmpLess[int](x.Key, y.Key)
+	}
+	for _, n := range []int{0, 1, 2, 3, 7, 8, 9, 100} {
+		l := NewList[Pair[int, int]]()
+		elems := []*Element[Pair[int, int]]{}
+		for i := 0; i < n; i++ {
+			elems = append(elems, l.PushBack(Pair[int, int]{Key: rand.Intn(5), Value: i}))
+		}
+		l.Sort(cmp)
+		if !l.IsSorted(cmp) || l.Len() != n {
+			panic(fmt.Sprintf("Expect %d sorted elements, but got %v", n, l))
+		}
+		// stable sort is the same as sorting by key then the original index
+		sort.Slice(elems, func(i, j int) bool {
+			if elems[i].Value.Key != elems[j].Value.Key {
+				return elems[i].Value.Key < elems[j].Value.Key
+			}
+			return elems[i].Value.Value < elems[j].Value.Value
+		})
+		prev := l.sentinel
+		for _, e := range elems {
+			if prev.next != e || e.prev != prev || e.list() != l {
+				panic(fmt.Sprintf("Expect stable sorted elements relinked, but got %v", l))
+			}
+			prev = e
+		}
+		if prev.next != l.sentinel || l.sentinel.prev != prev {
+			panic("Node connection wrong")
+		}
+	}
+
+	l, other := NewList[int](), NewList[int]()
+	e1, e3 := l.PushBack(1), l.PushBack(3)
+	e0, e1b, e4 := other.PushBack(0), other.PushBack(1), other.PushBack(4)
+	if !l.IsSorted(CmpLess[int]) || l.IsSorted(CmpGreater[int]) {
+		panic("IsSorted check failed")
+	}
+	l.Merge(other, CmpLess[int])
+	checkData(l, []*Element[int]{e0, e1, e1b, e3, e4})
+	checkData(other, []*Element[int]{})
+	l.Merge(l, CmpLess[int])
+	checkData(l, []*Element[int]{e0, e1, e1b, e3, e4})
+	other.PushBack(2)
+	l.Merge(other, CmpLess[int])
+	if l.String() != "[0 <-> 1 <-> 1 <-> 2 <-> 3 <-> 4]" {
+		panic(fmt.Sprintf("Expect merged list, but got %v", l))
+	}
+}
+
+func testListFind() {
+	l := NewList[int]()
+	e1, e2, e3, e4 := l.PushBack(1), l.PushBack(2), l.PushBack(3), l.PushBack(4)
+	even := func(x int) bool { return x%2 == 0 }
+	none := func(x int) bool { return x > 4 }
+
+	if l.FindFunc(even) != e2 || l.FindLast(even) != e4 || l.FindFunc(none) != nil || l.FindLast(none) != nil {
+		panic("FindFunc or FindLast failed")
+	}
+	if all := l.FindAll(even); len(all) != 2 || all[0] != e2 || all[1] != e4 {
+		panic(fmt.Sprintf("Expect FindAll to return 2 elements, but got %d", len(all)))
+	}
+	if len(l.FindAll(none)) != 0 {
+		panic("Expect FindAll to return no elements")
+	}
+	if ListFind[int](l, 3) != e3 || ListFind[int](l, 5) != nil || l.Find(1) != e1 {
+		panic("ListFind or Find failed")
+	}
+
+	if n := l.RemoveFunc(even); n != 2 {
+		panic(fmt.Sprintf("Expect RemoveFunc to remove 2 elements, but got %d", n))
+	}
+	checkData(l, []*Element[int]{e1, e3})
+	if n := l.RemoveFunc(none); n != 0 {
+		panic(fmt.Sprintf("Expect RemoveFunc to remove no elements, but got %d", n))
+	}
+	checkData(l, []*Element[int]{e1, e3})
+}
+
+func testListZeroValue() {
+	var holder struct {
+		l List[int]
+	}
+	l := &holder.l
+	checkData(l, []*Element[int]{})
+	if l.Front() != nil || l.Back() != nil || l.FindFunc(func(int) bool { return true }) != nil {
+		panic("Expect zero List to be empty")
+	}
+	l.Clear()
+	l.Sort(CmpLess[int])
+	l.PushBackList(NewList[int]())
+	l.Merge(NewList[int](), CmpLess[int])
+	checkData(l, []*Element[int]{})
+
+	e2 := l.PushBack(2)
+	e1 := l.PushFront(1)
+	checkData(l, []*Element[int]{e1, e2})
+
+	var other List[int]
+	other.SpliceBack(l)
+	checkData(&other, []*Element[int]{e1, e2})
+	checkData(l, []*Element[int]{})
+}
+
+func testListRange() {
+	l := NewList[int]()
+	elems := []*Element[int]{}
+	for i := 0; i < 6; i++ {
+		elems = append(elems, l.PushBack(i))
+	}
+	if l.Range(elems[3], elems[1]) != nil || l.Range(elems[0], NewList[int]().PushBack(0)) != nil {
+		panic("Expect Range to fail on invalid elements")
+	}
+
+	r := l.Range(elems[1], elems[4])
+	if r.Len() != 4 || r.Front() != elems[1] || r.Back() != elems[4] {
+		panic(fmt.Sprintf("Expect a range of 4 elements, but got %v", r.Values()))
+	}
+	r.Reverse()
+	checkData(l, []*Element[int]{elems[0], elems[4], elems[3], elems[2], elems[1], elems[5]})
+	if fmt.Sprint(r.Values()) != "[4 3 2 1]" || r.Front() != elems[4] {
+		panic(fmt.Sprintf("Expect reversed range [4 3 2 1], but got %v", r.Values()))
+	}
+	count := 0
+	r.Each(func(e *Element[int]) bool {
+		count++
+		return count < 2
+	})
+	if count != 2 {
+		panic(fmt.Sprintf("Expect Each to stop after 2 elements, but got %d", count))
+	}
+
+	owners := []*listOwner[int]{}
+	for _, e := range elems {
+		owners = append(owners, e.owner)
+	}
+	cut := r.Remove()
+	checkData(l, []*Element[int]{elems[0], elems[5]})
+	checkData(cut, []*Element[int]{elems[4], elems[3], elems[2], elems[1]})
+	for i, e := range elems {
+		if e.owner != owners[i] {
+			panic("Expect Remove not to update the elements")
+		}
+	}
+	l.PushBack(6)
+	l.MoveToFront(elems[3])
+	checkData(l, []*Element[int]{elems[0], elems[5], l.Back()})
+	r.Reverse()
+	checkData(cut, []*Element[int]{elems[1], elems[2], elems[3], elems[4]})
+
+	// single element and the whole list
+	r = cut.Range(elems[2], elems[2])
+	r.Reverse()
+	checkData(cut, []*Element[int]{elems[1], elems[2], elems[3], elems[4]})
+	r = cut.Range(elems[1], elems[4])
+	r.Reverse()
+	checkData(cut, []*Element[int]{elems[4], elems[3], elems[2], elems[1]})
+	r.Remove()
+	checkData(cut, []*Element[int]{})
+}
+
+func testListRotate() {
+	l := NewList[int]()
+	l.Rotate(1)
+	elems := []*Element[int]{}
+	for i := 0; i < 5; i++ {
+		elems = append(elems, l.PushBack(i))
+	}
+	l.Rotate(2)
+	checkData(l, []*Element[int]{elems[3], elems[4], elems[0], elems[1], elems[2]})
+	l.Rotate(-2)
+	checkData(l, []*Element[int]{elems[0], elems[1], elems[2], elems[3], elems[4]})
+	l.Rotate(4)
+	checkData(l, []*Element[int]{elems[1], elems[2], elems[3], elems[4], elems[0]})
+	l.Rotate(-4 + 10)
+	checkData(l, []*Element[int]{elems[0], elems[1], elems[2], elems[3], elems[4]})
+	l.Rotate(5)
+	checkData(l, []*Element[int]{elems[0], elems[1], elems[2], elems[3], elems[4]})
+}
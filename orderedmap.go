@@ -2,17 +2,18 @@ package container
 
 import "fmt"
 
-// OrderedMap is a hash map with the preservation of insertion order
+// OrderedMap is a hash map with the preservation of insertion order.
+// The zero value is an empty OrderedMap ready to use.
+// An OrderedMap must not be copied after first use.
 type OrderedMap[K comparable, V any] struct {
 	mp map[K]*Element[Pair[K,V]]
-	list *List[Pair[K,V]]
+	list List[Pair[K,V]]
 }
 
 // NewOrderedMap returns a new OrderedMap object
 func NewOrderedMap[K comparable, V any]() *OrderedMap[K,V] {
 	return &OrderedMap[K,V]{
 		mp: make(map[K]*Element[Pair[K,V]]),
-		list: NewList[Pair[K,V]](),
 	}
 }
 
@@ -47,6 +48,9 @@ func (m *OrderedMap[K,V]) Insert(k K, v V) {
 	if m.Has(k) {
 		m.mp[k].Value = Pair[K,V]{Key: k, Value: v}
 	} else {
+		if m.mp == nil {
+			m.mp = make(map[K]*Element[Pair[K,V]])
+		}
 		pair := Pair[K,V]{Key: k, Value: v}
 		e := m.list.PushBack(pair)
 		m.mp[k] = e
@@ -223,4 +227,26 @@ func testOrderedMap() {
 	checkOMapKV(k, v, ok, k, 2, true)
 	m.Clear()
 	checkOMapOrder(m, []string{}, []int{})
-}
\ No newline at end of file
+}
+
+func testOrderedMapZeroValue() {
+	var holder struct {
+		m OrderedMap[string, int]
+	}
+	m := &holder.m
+	checkOMapOrder(m, []string{}, []int{})
+	if _, ok := m.Get("apple"); ok || m.Has("apple") {
+		panic("Expect zero OrderedMap to be empty")
+	}
+	if _, ok := m.Remove("apple"); ok {
+		panic("Expect Remove on zero OrderedMap to fail")
+	}
+	if _, _, ok := m.PopFront(); ok {
+		panic("Expect PopFront on zero OrderedMap to fail")
+	}
+	m.Clear()
+	m.Insert("apple", 1)
+	m.Insert("banana", 2)
+	m.MoveToFront("banana")
+	checkOMapOrder(m, []string{"banana", "apple"}, []int{2, 1})
+}
@@ -0,0 +1,251 @@
+package container
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// OrderedMapInterface is the common interface of maps with keys sorted by a comparator,
+// such that code can switch backends (e.g. AVLTree, RBTree, BTree, SkipList, Treap)
+// by taking a constructor as a parameter
+type OrderedMapInterface[K, V any] interface {
+	// Has key in the map
+	Has(key K) bool
+	// Get key from the map, return value and whether the key is found
+	Get(key K) (V, bool)
+	// GetFloor returns the entry less than or equal to the given key if exists
+	GetFloor(key K) (K, V, bool)
+	// GetCeiling returns the entry greater than or equal to the given key if exists
+	GetCeiling(key K) (K, V, bool)
+	// GetLower returns the entry less than the given key if exists
+	GetLower(key K) (K, V, bool)
+	// GetHigher returns the entry greater than the given key if exists
+	GetHigher(key K) (K, V, bool)
+	// GetFirst returns the smallest entry if exists
+	GetFirst() (K, V, bool)
+	// GetLast returns the greatest entry if exists
+	GetLast() (K, V, bool)
+	// Insert a key-value pair, the value is overwritten if the key exists
+	Insert(key K, value V)
+	// Remove the entry with given key if exists
+	Remove(key K)
+	// Len returns the number of entries
+	Len() int
+	// Clear all entries
+	Clear()
+	// Each calls fn on each entry in ascending order of keys until fn returns false
+	Each(fn func(K,V) bool)
+	// Range calls fn on each entry with key between lo and hi in ascending order
+	// until fn returns false, whether lo and hi are included is decided by
+	// loInclusive and hiInclusive
+	Range(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool)
+	// RangeReverse is the same as Range but visits entries in descending order
+	RangeReverse(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool)
+}
+
+var (
+	_ OrderedMapInterface[int, int] = (*AVLTree[int, int])(nil)
+	_ OrderedMapInterface[int, int] = (*RBTree[int, int])(nil)
+	_ OrderedMapInterface[int, int] = (*BTree[int, int])(nil)
+	_ OrderedMapInterface[int, int] = (*SkipList[int, int])(nil)
+	_ OrderedMapInterface[int, int] = (*Treap[int, int])(nil)
+)
+
+// CheckOrderedMapConformance runs the shared test suite of OrderedMapInterface against
+// the maps created by newMap, such that other implementations can be checked as well.
+// Maps with a Validate() error method are also validated. It returns the first failure.
+func CheckOrderedMapConformance(newMap func(cmp func(int,int) int) OrderedMapInterface[int, int]) (err error) {
+	type failure struct {
+		error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			f, ok := r.(failure)
+			if !ok {
+				panic(r)
+			}
+			err = f.error
+		}
+	}()
+	fail := func(format string, args ...interface{}) {
+		panic(failure{fmt.Errorf(format, args...)})
+	}
+	checkEntry := func(op string, k, key, val int, ok bool, keys []int, pos int) {
+		if pos < 0 || pos >= len(keys) {
+			if ok {
+				fail("%s(%d): expect no entry, but got %d,%d", op, k, key, val)
+			}
+		} else if !ok || key != keys[pos] || val != -keys[pos] {
+			fail("%s(%d): expect %d,%d, but got %d,%d,%t", op, k, keys[pos], -keys[pos], key, val, ok)
+		}
+	}
+	checkKeys := func(m OrderedMapInterface[int, int], keys []int) {
+		if v, ok := m.(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				fail("Validate: %v", err)
+			}
+		}
+		if m.Len() != len(keys) {
+			fail("expect size %d, but got %d", len(keys), m.Len())
+		}
+		i := 0
+		m.Each(func(k, v int) bool {
+			if i >= len(keys) || k != keys[i] || v != -k {
+				fail("Each: unexpected entry %d,%d at %d", k, v, i)
+			}
+			i++
+			return true
+		})
+		if i != len(keys) {
+			fail("Each: expect %d entries, but got %d", len(keys), i)
+		}
+
+		for k := -1; k <= len(keys)*2+1; k++ {
+			pos := sort.SearchInts(keys, k) // the first key >= k
+			found := pos < len(keys) && keys[pos] == k
+			if v, ok := m.Get(k); ok != found || ok != m.Has(k) || (ok && v != -k) {
+				fail("Get(%d): got %d,%t", k, v, ok)
+			}
+			key, val, ok := m.GetCeiling(k)
+			checkEntry("GetCeiling", k, key, val, ok, keys, pos)
+			key, val, ok = m.GetLower(k)
+			checkEntry("GetLower", k, key, val, ok, keys, pos-1)
+			if found {
+				pos++
+			}
+			key, val, ok = m.GetHigher(k)
+			checkEntry("GetHigher", k, key, val, ok, keys, pos)
+			key, val, ok = m.GetFloor(k)
+			checkEntry("GetFloor", k, key, val, ok, keys, pos-1)
+		}
+		key, val, ok := m.GetFirst()
+		checkEntry("GetFirst", 0, key, val, ok, keys, 0)
+		key, val, ok = m.GetLast()
+		checkEntry("GetLast", 0, key, val, ok, keys, len(keys)-1)
+	}
+	checkRange := func(m OrderedMapInterface[int, int], keys []int, lo, hi int, loInc, hiInc bool) {
+		expect := []int{}
+		for _, k := range keys {
+			if (lo < k || (loInc && lo == k)) && (k < hi || (hiInc && k == hi)) {
+				expect = append(expect, k)
+			}
+		}
+		got, gotReverse := []int{}, []int{}
+		m.Range(lo, hi, loInc, hiInc, func(k, v int) bool {
+			got = append(got, k)
+			return true
+		})
+		m.RangeReverse(lo, hi, loInc, hiInc, func(k, v int) bool {
+			gotReverse = append(gotReverse, k)
+			return true
+		})
+		if len(got) != len(expect) || len(gotReverse) != len(expect) {
+			fail("Range(%d,%d,%t,%t): expect %v, but got %v and %v", lo, hi, loInc, hiInc, expect, got, gotReverse)
+		}
+		for i := range expect {
+			if got[i] != expect[i] || gotReverse[len(expect)-1-i] != expect[i] {
+				fail("Range(%d,%d,%t,%t): expect %v, but got %v and %v", lo, hi, loInc, hiInc, expect, got, gotReverse)
+			}
+		}
+	}
+
+	m := newMap(CmpLess[int])
+	checkKeys(m, []int{})
+	checkRange(m, []int{}, 0, 10, true, true)
+
+	// random inserts and removes against a sorted slice
+	rng := rand.New(rand.NewSource(1))
+	keys := []int{}
+	for i := 0; i < 2000; i++ {
+		k := rng.Intn(300)
+		pos := sort.SearchInts(keys, k)
+		found := pos < len(keys) && keys[pos] == k
+		if rng.Intn(3) > 0 {
+			m.Insert(k, -k)
+			if !found {
+				keys = append(keys[:pos], append([]int{k}, keys[pos:]...)...)
+			}
+		} else {
+			m.Remove(k)
+			if found {
+				keys = append(keys[:pos], keys[pos+1:]...)
+			}
+		}
+		if i%200 == 0 {
+			checkKeys(m, keys)
+		}
+	}
+	checkKeys(m, keys)
+	for i := 0; i < 100; i++ {
+		lo := rng.Intn(310) - 5
+		hi := lo + rng.Intn(310-lo) - 5
+		checkRange(m, keys, lo, hi, rng.Intn(2) == 0, rng.Intn(2) == 0)
+	}
+
+	// overwrite and early stop
+	m.Insert(keys[0], 1)
+	if v, _ := m.Get(keys[0]); v != 1 || m.Len() != len(keys) {
+		fail("expect overwritten value 1 and size %d, but got %d and %d", len(keys), v, m.Len())
+	}
+	m.Insert(keys[0], -keys[0])
+	count := 0
+	m.Each(func(k, v int) bool {
+		count++
+		return count < 2
+	})
+	m.RangeReverse(keys[0], keys[len(keys)-1], true, true, func(k, v int) bool {
+		count++
+		return false
+	})
+	if count != 3 {
+		fail("expect Each and RangeReverse to stop early, but got %d calls", count)
+	}
+
+	// the comparator decides the order
+	reversed := newMap(CmpGreater[int])
+	for _, k := range keys {
+		reversed.Insert(k, -k)
+	}
+	if key, _, _ := reversed.GetFirst(); key != keys[len(keys)-1] {
+		fail("expect first key %d with CmpGreater, but got %d", keys[len(keys)-1], key)
+	}
+
+	m.Clear()
+	checkKeys(m, []int{})
+	m.Insert(1, -1)
+	checkKeys(m, []int{1})
+	return nil
+}
+
+/////////////////////////////
+///////// Testing ///////////
+/////////////////////////////
+
+func testOrderedMapConformance() {
+	constructors := []struct {
+		name string
+		newMap func(cmp func(int,int) int) OrderedMapInterface[int, int]
+	}{
+		{"AVLTree", func(cmp func(int,int) int) OrderedMapInterface[int, int] { return NewAVLTree[int, int](cmp) }},
+		{"RBTree", func(cmp func(int,int) int) OrderedMapInterface[int, int] { return NewRBTree[int, int](cmp) }},
+		{"BTree(2)", func(cmp func(int,int) int) OrderedMapInterface[int, int] { return NewBTreeWithDegree[int, int](cmp, 2) }},
+		{"BTree(3)", func(cmp func(int,int) int) OrderedMapInterface[int, int] { return NewBTreeWithDegree[int, int](cmp, 3) }},
+		{"BTree(8)", func(cmp func(int,int) int) OrderedMapInterface[int, int] { return NewBTreeWithDegree[int, int](cmp, 8) }},
+		{"SkipList", func(cmp func(int,int) int) OrderedMapInterface[int, int] { return NewSkipListWithSeed[int, int](cmp, 1) }},
+		{"Treap", func(cmp func(int,int) int) OrderedMapInterface[int, int] { return NewTreapWithSeed[int, int](cmp, 1) }},
+	}
+	for _, c := range constructors {
+		if err := CheckOrderedMapConformance(c.newMap); err != nil {
+			panic(fmt.Sprintf("%s: %v", c.name, err))
+		}
+	}
+
+	// failures are reported as errors
+	err := CheckOrderedMapConformance(func(cmp func(int,int) int) OrderedMapInterface[int, int] {
+		return NewAVLTree[int, int](func(a, b int) int { return 0 })
+	})
+	if err == nil {
+		panic("Expect the conformance suite to fail on a map ignoring keys")
+	}
+}
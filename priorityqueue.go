@@ -1,6 +1,10 @@
 package container
 
-import "container/heap"
+import (
+	"container/heap"
+	"fmt"
+	"io"
+)
 
 // priorityQueue implements the heap.Interface interface
 type priorityQueue[T any] struct {
@@ -93,6 +97,41 @@ func (pq *PriorityQueue[T]) PopPush(x T) T {
 	return r
 }
 
+// renderTree converts the heap of the PriorityQueue to renderNodes
+func (pq *PriorityQueue[T]) renderTree(fmtVal func(T) string) *renderNode {
+	fmtVal = formatter[T](fmtVal)
+	h := pq.pq.h
+	var convert func(i int) *renderNode
+	convert = func(i int) *renderNode {
+		if i >= len(h) {
+			return nil
+		}
+		return &renderNode{
+			label: fmtVal(h[i]),
+			left: convert(2*i + 1),
+			right: convert(2*i + 2),
+		}
+	}
+	return convert(0)
+}
+
+// WriteDOT writes the heap layout of the PriorityQueue as a Graphviz DOT digraph,
+// where elements are formatted by fmtVal (fmt.Sprint if nil)
+func (pq *PriorityQueue[T]) WriteDOT(w io.Writer, fmtVal func(T) string) error {
+	return writeTreeDOT(w, "PriorityQueue", pq.renderTree(fmtVal))
+}
+
+// Pretty returns a sideways ASCII dump of the heap layout of the PriorityQueue,
+// where elements are formatted by fmtVal (fmt.Sprint if nil)
+func (pq *PriorityQueue[T]) Pretty(fmtVal func(T) string) string {
+	return prettyTree(pq.renderTree(fmtVal))
+}
+
+// String returns a sideways ASCII dump of the heap layout of the PriorityQueue
+func (pq *PriorityQueue[T]) String() string {
+	return pq.Pretty(nil)
+}
+
 /////////////////////////////
 ///////// Testing ///////////
 /////////////////////////////
@@ -1,14 +1,15 @@
 package container
 
-// Queue is an implementation of queue
+// Queue is an implementation of queue.
+// The zero value is an empty Queue ready to use.
+// A Queue must not be copied after first use.
 type Queue[T any] struct {
-	list *List[T]
+	list List[T]
 }
 
 // NewQueue returns a new Queue object
 func NewQueue[T any]() *Queue[T] {
-	l := NewList[T]()
-	return &Queue[T]{l}
+	return &Queue[T]{}
 }
 
 // Push adds a new element into the Queue
@@ -79,4 +80,18 @@ func testQueue() {
 
 	q.Clear()
 	checkQueueSize(q, 0)
-}
\ No newline at end of file
+}
+
+func testQueueZeroValue() {
+	var holder struct {
+		q Queue[int]
+	}
+	q := &holder.q
+	checkQueueSize(q, 0)
+	q.Clear()
+	q.Push(1)
+	q.Push(2)
+	checkQueueSize(q, 2)
+	checkQueueNum(q.Pop(), 1)
+	checkQueueNum(q.Top(), 2)
+}
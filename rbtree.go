@@ -1 +1,638 @@
-package container
\ No newline at end of file
+package container
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// rbColor is the color of a rbNode
+type rbColor bool
+
+const (
+	rbRed rbColor = false
+	rbBlack rbColor = true
+)
+
+// rbNode is the RBTree node structure
+type rbNode[K, V any] struct {
+	Key K
+	Value V
+	Left *rbNode[K,V]
+	Right *rbNode[K,V]
+	parent *rbNode[K,V]
+	color rbColor
+}
+
+// IsRed returns if a rbNode is red. nil node is black
+func (node *rbNode[K,V]) IsRed() bool {
+	return node != nil && node.color == rbRed
+}
+
+// entry of a rbNode, see bstNode
+func (node *rbNode[K,V]) entry() (K, V) {
+	return node.Key, node.Value
+}
+
+// children of a rbNode, see bstNode
+func (node *rbNode[K,V]) children() (*rbNode[K,V], *rbNode[K,V]) {
+	return node.Left, node.Right
+}
+
+// RBTree (Red Black Tree) data structure with no duplicated value.
+// Compared to AVLTree, it is less strictly balanced but needs at most
+// three rotations to rebalance after a removal.
+type RBTree[K, V any] struct {
+	root *rbNode[K,V]
+	cmp func(K,K) int
+	size int
+}
+
+// NewRBTree creates a new RBTree given a comparator of key type
+func NewRBTree[K,V any](cmp func(K,K) int) *RBTree[K,V] {
+	return &RBTree[K,V]{
+		cmp: cmp,
+	}
+}
+
+// Has key in the RBTree
+func (t *RBTree[K,V]) Has(key K) bool {
+	return t.find(key) != nil
+}
+
+// MustGet returns value of given key if the key exists, otherwise
+// returns zero-value of V
+func (t *RBTree[K,V]) MustGet(key K) (rVal V) {
+	if v, ok := t.Get(key); ok {
+		rVal = v
+	}
+	return
+}
+
+// Get key from RBTree, return value and whether the key is found
+// if not found, return value is the zero-value of type V
+func (t *RBTree[K,V]) Get(key K) (rVal V, ok bool) {
+	if node := t.find(key); node != nil {
+		rVal, ok = node.Value, true
+	}
+	return
+}
+
+// find the node with given key, returns nil if not found
+func (t *RBTree[K,V]) find(key K) *rbNode[K,V] {
+	node := t.root
+	for node != nil {
+		cmp := t.cmp(node.Key, key)
+		if cmp == 0 {
+			return node
+		}
+
+		if cmp > 0 {
+			node = node.Left
+		} else {
+			node = node.Right
+		}
+	}
+	return nil
+}
+
+// GetFloor returns the entry less than or equal to the given key if exists
+func (t *RBTree[K,V]) GetFloor(key K) (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		cmp := t.cmp(node.Key, key)
+		if cmp == 0 {
+			rKey, rVal, ok = node.Key, node.Value, true
+			return
+		}
+
+		if cmp > 0 {
+			node = node.Left
+		} else {
+			rKey, rVal, ok = node.Key, node.Value, true
+			node = node.Right
+		}
+	}
+	return
+}
+
+// GetCeiling returns the entry greater than or equal to the given key if exists
+func (t *RBTree[K,V]) GetCeiling(key K) (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		cmp := t.cmp(node.Key, key)
+		if cmp == 0 {
+			rKey, rVal, ok = node.Key, node.Value, true
+			return
+		}
+
+		if cmp > 0 {
+			rKey, rVal, ok = node.Key, node.Value, true
+			node = node.Left
+		} else {
+			node = node.Right
+		}
+	}
+	return
+}
+
+// GetLower returns the entry less than the given key if exists
+func (t *RBTree[K,V]) GetLower(key K) (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		cmp := t.cmp(node.Key, key)
+		if cmp >= 0 {
+			node = node.Left
+		} else {
+			rKey, rVal, ok = node.Key, node.Value, true
+			node = node.Right
+		}
+	}
+	return
+}
+
+// GetHigher returns the entry greater than the given key if exists
+func (t *RBTree[K,V]) GetHigher(key K) (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		cmp := t.cmp(node.Key, key)
+		if cmp <= 0 {
+			node = node.Right
+		} else {
+			rKey, rVal, ok = node.Key, node.Value, true
+			node = node.Left
+		}
+	}
+	return
+}
+
+// GetFirst returns the smallest element (according to cmp) in the RBTree if exists
+func (t *RBTree[K,V]) GetFirst() (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil && node.Left != nil {
+		node = node.Left
+	}
+	if node != nil {
+		rKey, rVal, ok = node.Key, node.Value, true
+	}
+	return
+}
+
+// GetLast returns the greatest element (according to cmp) in the RBTree if exists
+func (t *RBTree[K,V]) GetLast() (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil && node.Right != nil {
+		node = node.Right
+	}
+	if node != nil {
+		rKey, rVal, ok = node.Key, node.Value, true
+	}
+	return
+}
+
+// Len return the size of the RBTree
+func (t *RBTree[K,V]) Len() int {
+	return t.size
+}
+
+// Each calls fn on each entry in ascending order of keys. It stops once fn returns false.
+func (t *RBTree[K,V]) Each(fn func(K,V) bool) {
+	eachNode[*rbNode[K,V],K,V](t.root, fn)
+}
+
+// Range calls fn on each entry with key between lo and hi in ascending order,
+// whether lo and hi are included is decided by loInclusive and hiInclusive.
+// It stops once fn returns false.
+func (t *RBTree[K,V]) Range(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool) {
+	rangeNode[*rbNode[K,V],K,V](t.root, t.cmp, lo, hi, loInclusive, hiInclusive, false, fn)
+}
+
+// RangeReverse is the same as Range but visits entries in descending order
+func (t *RBTree[K,V]) RangeReverse(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool) {
+	rangeNode[*rbNode[K,V],K,V](t.root, t.cmp, lo, hi, loInclusive, hiInclusive, true, fn)
+}
+
+// Insert a key-value pair into the RBTree
+func (t *RBTree[K,V]) Insert(key K, value V) {
+	var parent *rbNode[K,V]
+	node, cmp := t.root, 0
+	for node != nil {
+		parent = node
+		cmp = t.cmp(node.Key, key)
+		if cmp == 0 {
+			node.Value = value
+			return
+		}
+
+		if cmp > 0 {
+			node = node.Left
+		} else {
+			node = node.Right
+		}
+	}
+
+	node = &rbNode[K,V]{
+		Key: key,
+		Value: value,
+		parent: parent,
+		color: rbRed,
+	}
+	if parent == nil {
+		t.root = node
+	} else if cmp > 0 {
+		parent.Left = node
+	} else {
+		parent.Right = node
+	}
+	t.size++
+	t.insertFix(node)
+}
+
+// fix the red-red violation introduced by inserting a red node
+func (t *RBTree[K,V]) insertFix(node *rbNode[K,V]) {
+	for node.parent.IsRed() { // a red parent is never the root, so grand exists
+		parent := node.parent
+		grand := parent.parent
+		if parent == grand.Left {
+			if uncle := grand.Right; uncle.IsRed() { // recolor and move up
+				parent.color, uncle.color, grand.color = rbBlack, rbBlack, rbRed
+				node = grand
+				continue
+			}
+			if node == parent.Right { // left-right case
+				t.rotateLeft(parent)
+				node, parent = parent, node
+			}
+			parent.color, grand.color = rbBlack, rbRed
+			t.rotateRight(grand)
+		} else {
+			if uncle := grand.Left; uncle.IsRed() { // recolor and move up
+				parent.color, uncle.color, grand.color = rbBlack, rbBlack, rbRed
+				node = grand
+				continue
+			}
+			if node == parent.Left { // right-left case
+				t.rotateRight(parent)
+				node, parent = parent, node
+			}
+			parent.color, grand.color = rbBlack, rbRed
+			t.rotateLeft(grand)
+		}
+	}
+	t.root.color = rbBlack
+}
+
+// Remove the node with given key in the RBTree if exists
+// it has no-op if key is not in the tree
+func (t *RBTree[K,V]) Remove(key K) {
+	node := t.find(key)
+	if node == nil {
+		return
+	}
+
+	if node.Left != nil && node.Right != nil { // both sides have children
+		nxtNode := t.successor(node)
+		node.Key, node.Value = nxtNode.Key, nxtNode.Value
+		node = nxtNode
+	}
+
+	// node has at most one child now
+	child := node.Left
+	if child == nil {
+		child = node.Right
+	}
+	parent := node.parent
+	if child != nil {
+		child.parent = parent
+	}
+	t.replaceChild(parent, node, child)
+	t.size--
+
+	if node.color == rbBlack {
+		t.removeFix(child, parent)
+	}
+}
+
+// fix the missing black of node after removing, parent is given since node may be nil
+func (t *RBTree[K,V]) removeFix(node, parent *rbNode[K,V]) {
+	for node != t.root && !node.IsRed() {
+		if node == parent.Left {
+			sibling := parent.Right
+			if sibling.IsRed() {
+				sibling.color, parent.color = rbBlack, rbRed
+				t.rotateLeft(parent)
+				sibling = parent.Right
+			}
+			if !sibling.Left.IsRed() && !sibling.Right.IsRed() {
+				sibling.color = rbRed
+				node, parent = parent, parent.parent
+				continue
+			}
+			if !sibling.Right.IsRed() {
+				sibling.Left.color, sibling.color = rbBlack, rbRed
+				t.rotateRight(sibling)
+				sibling = parent.Right
+			}
+			sibling.color, parent.color = parent.color, rbBlack
+			sibling.Right.color = rbBlack
+			t.rotateLeft(parent)
+		} else {
+			sibling := parent.Left
+			if sibling.IsRed() {
+				sibling.color, parent.color = rbBlack, rbRed
+				t.rotateRight(parent)
+				sibling = parent.Left
+			}
+			if !sibling.Left.IsRed() && !sibling.Right.IsRed() {
+				sibling.color = rbRed
+				node, parent = parent, parent.parent
+				continue
+			}
+			if !sibling.Left.IsRed() {
+				sibling.Right.color, sibling.color = rbBlack, rbRed
+				t.rotateLeft(sibling)
+				sibling = parent.Left
+			}
+			sibling.color, parent.color = parent.color, rbBlack
+			sibling.Left.color = rbBlack
+			t.rotateRight(parent)
+		}
+		node = t.root
+	}
+	if node != nil {
+		node.color = rbBlack
+	}
+}
+
+// find the next greater element
+func (t *RBTree[K,V]) successor(node *rbNode[K,V]) *rbNode[K,V] {
+	node = node.Right
+	for node.Left != nil {
+		node = node.Left
+	}
+	return node
+}
+
+// replace the child old of parent with node, parent is nil if old is the root
+func (t *RBTree[K,V]) replaceChild(parent, old, node *rbNode[K,V]) {
+	if parent == nil {
+		t.root = node
+	} else if parent.Left == old {
+		parent.Left = node
+	} else {
+		parent.Right = node
+	}
+}
+
+/*
+		n							r
+	  /   \						  /   \
+	l	   r     ====>           n    rr
+		  / \					/\
+		 rl rr				   l  rl
+*/
+func (t *RBTree[K,V]) rotateLeft(node *rbNode[K,V]) {
+	root := node.Right
+	node.Right = root.Left
+	if root.Left != nil {
+		root.Left.parent = node
+	}
+	root.parent = node.parent
+	t.replaceChild(node.parent, node, root)
+	root.Left = node
+	node.parent = root
+}
+
+/*
+			n							l
+		  /   \						  /   \
+		l	   r     ====>           ll    n
+	   / \							  	  / \
+	  ll lr				   		   	 lr  r
+*/
+func (t *RBTree[K,V]) rotateRight(node *rbNode[K,V]) {
+	root := node.Left
+	node.Left = root.Right
+	if root.Right != nil {
+		root.Right.parent = node
+	}
+	root.parent = node.parent
+	t.replaceChild(node.parent, node, root)
+	root.Right = node
+	node.parent = root
+}
+
+// Clear all element in the RBTree
+func (t *RBTree[K,V]) Clear() {
+	t.root = nil
+	t.size = 0
+}
+
+// Validate checks keys are strictly increasing (according to cmp), parent pointers, sizes
+// and red-black properties are correct, returns an error at the first offending key
+func (t *RBTree[K,V]) Validate() error {
+	if t.root.IsRed() {
+		return fmt.Errorf("root %v is red", t.root.Key)
+	}
+	if t.root != nil && t.root.parent != nil {
+		return fmt.Errorf("root %v has a parent", t.root.Key)
+	}
+	_, size, err := t.validate(t.root, nil, nil)
+	if err == nil && size != t.size {
+		err = fmt.Errorf("tree has %d nodes, but size is %d", size, t.size)
+	}
+	return err
+}
+
+// recursively validate the subtree, where keys must be between lo and hi if not nil.
+// It returns the black height and the number of nodes of the subtree
+func (t *RBTree[K,V]) validate(node, lo, hi *rbNode[K,V]) (int, int, error) {
+	if node == nil {
+		return 1, 0, nil
+	}
+
+	if t.cmp(node.Key, node.Key) != 0 {
+		return 0, 0, fmt.Errorf("key %v is not equal to itself", node.Key)
+	}
+	if lo != nil && (t.cmp(lo.Key, node.Key) >= 0 || t.cmp(node.Key, lo.Key) <= 0) {
+		return 0, 0, fmt.Errorf("key %v is not greater than %v", node.Key, lo.Key)
+	}
+	if hi != nil && (t.cmp(node.Key, hi.Key) >= 0 || t.cmp(hi.Key, node.Key) <= 0) {
+		return 0, 0, fmt.Errorf("key %v is not less than %v", node.Key, hi.Key)
+	}
+	if (node.Left != nil && node.Left.parent != node) || (node.Right != nil && node.Right.parent != node) {
+		return 0, 0, fmt.Errorf("key %v has a child with wrong parent", node.Key)
+	}
+	if node.IsRed() && (node.Left.IsRed() || node.Right.IsRed()) {
+		return 0, 0, fmt.Errorf("red key %v has a red child", node.Key)
+	}
+
+	lbh, lsize, err := t.validate(node.Left, lo, node)
+	if err != nil {
+		return 0, 0, err
+	}
+	rbh, rsize, err := t.validate(node.Right, node, hi)
+	if err != nil {
+		return 0, 0, err
+	}
+	if lbh != rbh {
+		return 0, 0, fmt.Errorf("key %v has black height %d on left and %d on right", node.Key, lbh, rbh)
+	}
+	if node.color == rbBlack {
+		lbh++
+	}
+	return lbh, lsize + rsize + 1, nil
+}
+
+/////////////////////////////
+///////// Testing ///////////
+/////////////////////////////
+
+func checkRBTreeKeys(t *RBTree[int, int], expect []int) {
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+	if t.Len() != len(expect) {
+		panic(fmt.Sprintf("Expect tree size to be %d, but got %d.\n", len(expect), t.Len()))
+	}
+	for _, k := range expect {
+		if v, ok := t.Get(k); !ok || v != -k {
+			panic(fmt.Sprintf("Expect key %d with value %d, but got %d,%t.\n", k, -k, v, ok))
+		}
+	}
+}
+
+func testRBTree() {
+	t := NewRBTree[int, int](CmpLess[int])
+	checkRBTreeKeys(t, []int{})
+
+	var key, val int
+	var ok bool
+
+	// test one entry
+	t.Insert(1, -1)
+	checkRBTreeKeys(t, []int{1})
+	key, val, ok = t.GetFloor(1)
+	checkAVLTreeElement(key, val, ok, 1, -1, true)
+	key, val, ok = t.GetLower(1)
+	checkAVLTreeElement(key, val, ok, 0, 0, false)
+	t.Remove(1)
+	checkRBTreeKeys(t, []int{})
+
+	// test navigation
+	for _, k := range []int{3, 1, 5, 2, 4} {
+		t.Insert(k, -k)
+	}
+	checkRBTreeKeys(t, []int{1, 2, 3, 4, 5})
+	key, val, ok = t.GetLower(3)
+	checkAVLTreeElement(key, val, ok, 2, -2, true)
+	key, val, ok = t.GetHigher(4)
+	checkAVLTreeElement(key, val, ok, 5, -5, true)
+	key, val, ok = t.GetHigher(5)
+	checkAVLTreeElement(key, val, ok, 0, 0, false)
+	key, val, ok = t.GetCeiling(0)
+	checkAVLTreeElement(key, val, ok, 1, -1, true)
+	key, val, ok = t.GetFloor(6)
+	checkAVLTreeElement(key, val, ok, 5, -5, true)
+	key, val, ok = t.GetFirst()
+	checkAVLTreeElement(key, val, ok, 1, -1, true)
+	key, val, ok = t.GetLast()
+	checkAVLTreeElement(key, val, ok, 5, -5, true)
+	t.Insert(3, -3) // overwrite
+	checkRBTreeKeys(t, []int{1, 2, 3, 4, 5})
+	t.Remove(6) // test no-op
+	checkRBTreeKeys(t, []int{1, 2, 3, 4, 5})
+	t.Remove(3)
+	checkRBTreeKeys(t, []int{1, 2, 4, 5})
+	t.Clear()
+	checkRBTreeKeys(t, []int{})
+
+	// ascending and descending insertion
+	keys := []int{}
+	for i := 0; i < 64; i++ {
+		t.Insert(i, -i)
+		keys = append(keys, i)
+		checkRBTreeKeys(t, keys)
+	}
+	for i := 0; i < 64; i++ {
+		t.Remove(i)
+		checkRBTreeKeys(t, keys[i+1:])
+	}
+	for i := 63; i >= 0; i-- {
+		t.Insert(i, -i)
+	}
+	for i := 63; i >= 0; i-- {
+		t.Remove(i)
+		checkRBTreeKeys(t, keys[:i])
+	}
+
+	// random insert and remove
+	uniqueNums := make(map[int]struct{})
+	for i := 0; i < 200; i++ {
+		num := rand.Intn(100)
+		if rand.Intn(3) == 0 {
+			t.Remove(num)
+			delete(uniqueNums, num)
+		} else {
+			t.Insert(num, -num)
+			uniqueNums[num] = struct{}{}
+		}
+		keys = keys[:0]
+		for k := range uniqueNums {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+		checkRBTreeKeys(t, keys)
+	}
+}
+
+func testRBTreeValidate() {
+	t := NewRBTree[int, int](CmpLess[int])
+	for i := 0; i < 10; i++ {
+		t.Insert(i, -i)
+	}
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+
+	key := t.root.Left.Key
+	t.root.Left.Key = 100
+	if t.Validate() == nil {
+		panic("Expect Validate to fail on unsorted keys")
+	}
+	t.root.Left.Key = key
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+
+	t.root.color = rbRed
+	if t.Validate() == nil {
+		panic("Expect Validate to fail on red root")
+	}
+	t.root.color = rbBlack
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+
+	// a red leaf turned black only breaks the black heights
+	leaf := t.root
+	for leaf.Right != nil {
+		leaf = leaf.Right
+	}
+	if !leaf.IsRed() || leaf.Left != nil {
+		panic(fmt.Sprintf("Expect the last key %v to be a red leaf", leaf.Key))
+	}
+	leaf.color = rbBlack
+	if t.Validate() == nil {
+		panic("Expect Validate to fail on different black heights")
+	}
+	leaf.color = rbRed
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+
+	t.size++
+	if t.Validate() == nil {
+		panic("Expect Validate to fail on wrong size")
+	}
+}
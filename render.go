@@ -0,0 +1,180 @@
+package container
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// renderNode is a binary tree node holding only the label to be rendered,
+// containers are converted to renderNodes to share the rendering code
+type renderNode struct {
+	label string
+	left *renderNode
+	right *renderNode
+}
+
+// prettyTree renders the tree sideways in ASCII, where the root is at the left,
+// right children are above and left children are below their parents, e.g.
+//
+//	    /-- 5
+//	/-- 4
+//	|   \-- 3
+//	2
+//	\-- 1
+func prettyTree(root *renderNode) string {
+	var sb strings.Builder
+	root.pretty(&sb, "", "", "")
+	return sb.String()
+}
+
+// write the subtree with prefixes of lines above, of itself and below it
+func (node *renderNode) pretty(sb *strings.Builder, prefixUp, prefix, prefixDown string) {
+	if node == nil {
+		return
+	}
+	node.right.pretty(sb, prefixUp+"    ", prefixUp+"/-- ", prefixUp+"|   ")
+	sb.WriteString(prefix + node.label + "\n")
+	node.left.pretty(sb, prefixDown+"|   ", prefixDown+"\\-- ", prefixDown+"    ")
+}
+
+// writeTreeDOT writes the tree as a Graphviz DOT digraph. A missing child is drawn as
+// an invisible node if its sibling exists, such that left and right are kept in layout.
+func writeTreeDOT(w io.Writer, name string, root *renderNode) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %s {\n", name)
+	id := 0
+	var walk func(node *renderNode) int
+	walk = func(node *renderNode) int {
+		cur := id
+		id++
+		fmt.Fprintf(&sb, "\tn%d [label=%q];\n", cur, node.label)
+		if node.left == nil && node.right == nil {
+			return cur
+		}
+		for _, child := range []*renderNode{node.left, node.right} {
+			if child == nil {
+				fmt.Fprintf(&sb, "\tn%d [style=invis];\n\tn%d -> n%d [style=invis];\n", id, cur, id)
+				id++
+			} else {
+				fmt.Fprintf(&sb, "\tn%d -> n%d;\n", cur, walk(child))
+			}
+		}
+		return cur
+	}
+	if root != nil {
+		walk(root)
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeChainDOT writes labels as a chain of doubly linked nodes in Graphviz DOT digraph
+func writeChainDOT(w io.Writer, name string, labels []string) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %s {\n\trankdir=LR;\n", name)
+	for i, label := range labels {
+		fmt.Fprintf(&sb, "\tn%d [label=%q];\n", i, label)
+		if i > 0 {
+			fmt.Fprintf(&sb, "\tn%d -> n%d [dir=both];\n", i-1, i)
+		}
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// formatter returns fmtVal if it is not nil, otherwise fmt.Sprint
+func formatter[T any](fmtVal func(T) string) func(T) string {
+	if fmtVal != nil {
+		return fmtVal
+	}
+	return func(t T) string {
+		return fmt.Sprint(t)
+	}
+}
+
+/////////////////////////////
+///////// Testing ///////////
+/////////////////////////////
+
+func checkRender(got, expect string) {
+	if got != expect {
+		panic(fmt.Sprintf("Expect rendering:\n%s\nbut got:\n%s", expect, got))
+	}
+}
+
+func testRender() {
+	t := NewAVLTree[int, int](CmpLess[int])
+	checkRender(t.String(), "")
+	for _, k := range []int{2, 1, 4, 3, 5} {
+		t.Insert(k, 0)
+	}
+	checkRender(t.String(), ""+
+		"    /-- 5 (h=1, bal=0)\n"+
+		"/-- 4 (h=2, bal=0)\n"+
+		"|   \\-- 3 (h=1, bal=0)\n"+
+		"2 (h=3, bal=-1)\n"+
+		"\\-- 1 (h=1, bal=0)\n")
+
+	var sb strings.Builder
+	if err := t.WriteDOT(&sb, func(k int) string { return fmt.Sprintf("k%d", k) }); err != nil {
+		panic(err)
+	}
+	checkRender(sb.String(), ""+
+		"digraph AVLTree {\n"+
+		"\tn0 [label=\"k2 (h=3, bal=-1)\"];\n"+
+		"\tn1 [label=\"k1 (h=1, bal=0)\"];\n"+
+		"\tn0 -> n1;\n"+
+		"\tn2 [label=\"k4 (h=2, bal=0)\"];\n"+
+		"\tn3 [label=\"k3 (h=1, bal=0)\"];\n"+
+		"\tn2 -> n3;\n"+
+		"\tn4 [label=\"k5 (h=1, bal=0)\"];\n"+
+		"\tn2 -> n4;\n"+
+		"\tn0 -> n2;\n"+
+		"}\n")
+
+	pq := NewPQ[int](CmpLess[int])
+	for _, x := range []int{3, 1, 2, 4} {
+		pq.Push(x)
+	}
+	checkRender(pq.String(), ""+
+		"/-- 2\n"+
+		"1\n"+
+		"\\-- 3\n"+
+		"    \\-- 4\n")
+	sb.Reset()
+	if err := pq.WriteDOT(&sb, nil); err != nil {
+		panic(err)
+	}
+	checkRender(sb.String(), ""+
+		"digraph PriorityQueue {\n"+
+		"\tn0 [label=\"1\"];\n"+
+		"\tn1 [label=\"3\"];\n"+
+		"\tn2 [label=\"4\"];\n"+
+		"\tn1 -> n2;\n"+
+		"\tn3 [style=invis];\n"+
+		"\tn1 -> n3 [style=invis];\n"+
+		"\tn0 -> n1;\n"+
+		"\tn4 [label=\"2\"];\n"+
+		"\tn0 -> n4;\n"+
+		"}\n")
+
+	l := NewList[int]()
+	checkRender(l.String(), "[]")
+	l.PushBack(1)
+	l.PushBack(2)
+	checkRender(l.String(), "[1 <-> 2]")
+	sb.Reset()
+	if err := l.WriteDOT(&sb, nil); err != nil {
+		panic(err)
+	}
+	checkRender(sb.String(), ""+
+		"digraph List {\n"+
+		"\trankdir=LR;\n"+
+		"\tn0 [label=\"1\"];\n"+
+		"\tn1 [label=\"2\"];\n"+
+		"\tn0 -> n1 [dir=both];\n"+
+		"}\n")
+}
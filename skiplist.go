@@ -0,0 +1,465 @@
+package container
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// maxSkipListLevel is the maximum number of levels of a SkipList, which is enough
+// for billions of entries since a node is promoted to the next level with probability 1/4
+const maxSkipListLevel = 32
+
+// skipListLevel is a forward link of a skipListNode, where span is the number of
+// nodes skipped by the link, i.e. the difference of ranks. A nil link spans to the end.
+type skipListLevel[K, V any] struct {
+	next *skipListNode[K,V]
+	span int
+}
+
+// skipListNode is the SkipList node structure
+type skipListNode[K, V any] struct {
+	Key K
+	Value V
+	prev *skipListNode[K,V] // the previous node at the lowest level, nil for the first node
+	levels []skipListLevel[K,V]
+}
+
+// Next returns the next node at the lowest level, nil if it is the last node
+func (node *skipListNode[K,V]) Next() *skipListNode[K,V] {
+	return node.levels[0].next
+}
+
+// SkipList is an ordered map with no duplicated keys, built on sorted linked lists of
+// multiple levels, where each level skips about 3/4 nodes of the level below. Spans of
+// links are counted such that entries can be accessed by index in O(log n) expected time.
+// As other containers, a SkipList is safe for concurrent reads without writers.
+type SkipList[K, V any] struct {
+	head *skipListNode[K,V] // sentinel node with maxSkipListLevel levels
+	cmp func(K,K) int
+	rng *rand.Rand
+	level int
+	size int
+}
+
+// NewSkipList creates a new SkipList given a comparator of key type,
+// levels of nodes are drawn from a randomly seeded source
+func NewSkipList[K,V any](cmp func(K,K) int) *SkipList[K,V] {
+	return NewSkipListWithSeed[K,V](cmp, rand.Int63())
+}
+
+// NewSkipListWithSeed creates a new SkipList given a comparator of key type and the seed
+// of levels, such that the same operations build the same SkipList for reproducibility
+func NewSkipListWithSeed[K,V any](cmp func(K,K) int, seed int64) *SkipList[K,V] {
+	return &SkipList[K,V]{
+		head: &skipListNode[K,V]{levels: make([]skipListLevel[K,V], maxSkipListLevel)},
+		cmp: cmp,
+		rng: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// randomLevel returns the level of a new node, which is l with probability (3/4)*(1/4)^(l-1)
+func (s *SkipList[K,V]) randomLevel() int {
+	level := 1
+	for level < maxSkipListLevel && s.rng.Int63()&3 == 0 {
+		level++
+	}
+	return level
+}
+
+// findLess returns the last node with key less than the given key (less than or equal
+// to if inclusive), which is the head if no such node
+func (s *SkipList[K,V]) findLess(key K, inclusive bool) *skipListNode[K,V] {
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for next := x.levels[i].next; next != nil; next = x.levels[i].next {
+			if cmp := s.cmp(next.Key, key); cmp > 0 || (cmp == 0 && !inclusive) {
+				break
+			}
+			x = next
+		}
+	}
+	return x
+}
+
+// Has key in the SkipList
+func (s *SkipList[K,V]) Has(key K) bool {
+	_, ok := s.Get(key)
+	return ok
+}
+
+// MustGet returns value of given key if the key exists, otherwise
+// returns zero-value of V
+func (s *SkipList[K,V]) MustGet(key K) (rVal V) {
+	if v, ok := s.Get(key); ok {
+		rVal = v
+	}
+	return
+}
+
+// Get key from SkipList, return value and whether the key is found
+// if not found, return value is the zero-value of type V
+func (s *SkipList[K,V]) Get(key K) (rVal V, ok bool) {
+	if node := s.findLess(key, false).Next(); node != nil && s.cmp(node.Key, key) == 0 {
+		rVal, ok = node.Value, true
+	}
+	return
+}
+
+// entry returns the key and value of node, ok is false if node is nil or the head
+func (s *SkipList[K,V]) entry(node *skipListNode[K,V]) (rKey K, rVal V, ok bool) {
+	if node != nil && node != s.head {
+		rKey, rVal, ok = node.Key, node.Value, true
+	}
+	return
+}
+
+// GetFloor returns the entry less than or equal to the given key if exists
+func (s *SkipList[K,V]) GetFloor(key K) (rKey K, rVal V, ok bool) {
+	return s.entry(s.findLess(key, true))
+}
+
+// GetCeiling returns the entry greater than or equal to the given key if exists
+func (s *SkipList[K,V]) GetCeiling(key K) (rKey K, rVal V, ok bool) {
+	return s.entry(s.findLess(key, false).Next())
+}
+
+// GetLower returns the entry less than the given key if exists
+func (s *SkipList[K,V]) GetLower(key K) (rKey K, rVal V, ok bool) {
+	return s.entry(s.findLess(key, false))
+}
+
+// GetHigher returns the entry greater than the given key if exists
+func (s *SkipList[K,V]) GetHigher(key K) (rKey K, rVal V, ok bool) {
+	return s.entry(s.findLess(key, true).Next())
+}
+
+// GetFirst returns the smallest element (according to cmp) in the SkipList if exists
+func (s *SkipList[K,V]) GetFirst() (rKey K, rVal V, ok bool) {
+	return s.entry(s.head.Next())
+}
+
+// GetLast returns the greatest element (according to cmp) in the SkipList if exists
+func (s *SkipList[K,V]) GetLast() (rKey K, rVal V, ok bool) {
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil {
+			x = x.levels[i].next
+		}
+	}
+	return s.entry(x)
+}
+
+// Len return the size of the SkipList
+func (s *SkipList[K,V]) Len() int {
+	return s.size
+}
+
+// Rank returns the number of keys strictly less than the given key
+func (s *SkipList[K,V]) Rank(key K) int {
+	return s.rank(key, false)
+}
+
+// rank returns the number of keys less than the given key (less than or equal to if inclusive)
+func (s *SkipList[K,V]) rank(key K, inclusive bool) int {
+	rank, x := 0, s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for next := x.levels[i].next; next != nil; next = x.levels[i].next {
+			if cmp := s.cmp(next.Key, key); cmp > 0 || (cmp == 0 && !inclusive) {
+				break
+			}
+			rank += x.levels[i].span
+			x = next
+		}
+	}
+	return rank
+}
+
+// Select returns the i-th (0-indexed) smallest entry (according to cmp) if exists,
+// the entry exists if and only if 0 <= i < s.Len()
+func (s *SkipList[K,V]) Select(i int) (rKey K, rVal V, ok bool) {
+	if i < 0 || i >= s.size {
+		return
+	}
+	rank, x := 0, s.head
+	for lvl := s.level - 1; lvl >= 0; lvl-- {
+		for x.levels[lvl].next != nil && rank+x.levels[lvl].span <= i+1 {
+			rank += x.levels[lvl].span
+			x = x.levels[lvl].next
+		}
+		if rank == i+1 {
+			break
+		}
+	}
+	return s.entry(x)
+}
+
+// CountRange returns the number of keys between lo and hi (both inclusive)
+func (s *SkipList[K,V]) CountRange(lo, hi K) int {
+	if s.cmp(lo, hi) > 0 {
+		return 0
+	}
+	return s.rank(hi, true) - s.rank(lo, false)
+}
+
+// Each calls fn on each entry in ascending order of keys. It stops once fn returns false.
+func (s *SkipList[K,V]) Each(fn func(K,V) bool) {
+	for x := s.head.Next(); x != nil; x = x.Next() {
+		if !fn(x.Key, x.Value) {
+			return
+		}
+	}
+}
+
+// Range calls fn on each entry with key between lo and hi in ascending order,
+// whether lo and hi are included is decided by loInclusive and hiInclusive.
+// It stops once fn returns false.
+func (s *SkipList[K,V]) Range(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool) {
+	for x := s.findLess(lo, !loInclusive).Next(); x != nil; x = x.Next() {
+		if cmp := s.cmp(x.Key, hi); cmp > 0 || (cmp == 0 && !hiInclusive) {
+			return
+		}
+		if !fn(x.Key, x.Value) {
+			return
+		}
+	}
+}
+
+// RangeReverse is the same as Range but visits entries in descending order
+func (s *SkipList[K,V]) RangeReverse(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool) {
+	x := s.findLess(hi, hiInclusive)
+	if x == s.head {
+		return
+	}
+	for ; x != nil; x = x.prev {
+		if cmp := s.cmp(x.Key, lo); cmp < 0 || (cmp == 0 && !loInclusive) {
+			return
+		}
+		if !fn(x.Key, x.Value) {
+			return
+		}
+	}
+}
+
+// Insert a key-value pair into the SkipList
+func (s *SkipList[K,V]) Insert(key K, value V) {
+	var update [maxSkipListLevel]*skipListNode[K,V] // the last node before key at each level
+	var rank [maxSkipListLevel]int // rank of update at each level
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		if i < s.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.levels[i].next != nil && s.cmp(x.levels[i].next.Key, key) < 0 {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+	if next := x.Next(); next != nil && s.cmp(next.Key, key) == 0 {
+		next.Value = value
+		return
+	}
+
+	level := s.randomLevel()
+	for i := s.level; i < level; i++ {
+		rank[i] = 0
+		update[i] = s.head
+		s.head.levels[i].span = s.size
+	}
+	s.level = max(s.level, level)
+
+	node := &skipListNode[K,V]{
+		Key: key,
+		Value: value,
+		levels: make([]skipListLevel[K,V], level),
+	}
+	for i := 0; i < level; i++ {
+		node.levels[i].next = update[i].levels[i].next
+		update[i].levels[i].next = node
+		node.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+	for i := level; i < s.level; i++ { // links above the node skip one more node
+		update[i].levels[i].span++
+	}
+
+	if update[0] != s.head {
+		node.prev = update[0]
+	}
+	if next := node.Next(); next != nil {
+		next.prev = node
+	}
+	s.size++
+}
+
+// Remove the node with given key in the SkipList if exists
+// it has no-op if key is not in the skip list
+func (s *SkipList[K,V]) Remove(key K) {
+	var update [maxSkipListLevel]*skipListNode[K,V] // the last node before key at each level
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && s.cmp(x.levels[i].next.Key, key) < 0 {
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+	node := x.Next()
+	if node == nil || s.cmp(node.Key, key) != 0 {
+		return
+	}
+
+	for i := 0; i < s.level; i++ {
+		if update[i].levels[i].next == node {
+			update[i].levels[i].span += node.levels[i].span - 1
+			update[i].levels[i].next = node.levels[i].next
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+	if next := node.Next(); next != nil {
+		next.prev = node.prev
+	}
+	for s.level > 0 && s.head.levels[s.level-1].next == nil {
+		s.head.levels[s.level-1].span = 0
+		s.level--
+	}
+	s.size--
+}
+
+// Clear all element in the SkipList
+func (s *SkipList[K,V]) Clear() {
+	for i := range s.head.levels {
+		s.head.levels[i] = skipListLevel[K,V]{}
+	}
+	s.level = 0
+	s.size = 0
+}
+
+// Validate checks keys are strictly increasing (according to cmp), levels, spans, backward
+// links and size are correct, returns an error at the first offending key
+func (s *SkipList[K,V]) Validate() error {
+	ranks := map[*skipListNode[K,V]]int{s.head: 0}
+	var prev *skipListNode[K,V]
+	for x := s.head.Next(); x != nil; x = x.Next() {
+		if s.cmp(x.Key, x.Key) != 0 {
+			return fmt.Errorf("key %v is not equal to itself", x.Key)
+		}
+		if prev != nil && (s.cmp(prev.Key, x.Key) >= 0 || s.cmp(x.Key, prev.Key) <= 0) {
+			return fmt.Errorf("key %v is not greater than %v", x.Key, prev.Key)
+		}
+		if x.prev != prev {
+			return fmt.Errorf("key %v has wrong backward link", x.Key)
+		}
+		if len(x.levels) == 0 || len(x.levels) > s.level {
+			return fmt.Errorf("key %v has %d levels, expect between 1 and %d", x.Key, len(x.levels), s.level)
+		}
+		ranks[x] = len(ranks)
+		prev = x
+	}
+	if size := len(ranks) - 1; s.size != size {
+		return fmt.Errorf("skip list has size %d, expect %d", s.size, size)
+	}
+	if s.level > 0 && s.head.levels[s.level-1].next == nil {
+		return fmt.Errorf("skip list has empty level %d", s.level-1)
+	}
+
+	for i := 0; i < s.level; i++ {
+		for x := s.head; x != nil; x = x.levels[i].next {
+			next, span := x.levels[i].next, s.size-ranks[x]
+			if next != nil {
+				span = ranks[next] - ranks[x]
+				if len(next.levels) <= i {
+					return fmt.Errorf("key %v is linked at level %d, but has %d levels", next.Key, i, len(next.levels))
+				}
+			}
+			if x.levels[i].span != span {
+				return fmt.Errorf("link at level %d after rank %d has span %d, expect %d", i, ranks[x], x.levels[i].span, span)
+			}
+		}
+	}
+	return nil
+}
+
+/////////////////////////////
+///////// Testing ///////////
+/////////////////////////////
+
+func checkSkipListKeys(s *SkipList[int, int], keys []int) {
+	if err := s.Validate(); err != nil {
+		panic(err)
+	}
+	if s.Len() != len(keys) {
+		panic(fmt.Sprintf("Expect size %d, but got %d", len(keys), s.Len()))
+	}
+	for i, k := range keys {
+		if key, val, ok := s.Select(i); !ok || key != k || val != -k {
+			panic(fmt.Sprintf("Select(%d): expect %d,%d, but got %d,%d,%t", i, k, -k, key, val, ok))
+		}
+		if rank := s.Rank(k); rank != i {
+			panic(fmt.Sprintf("Rank(%d): expect %d, but got %d", k, i, rank))
+		}
+	}
+	if _, _, ok := s.Select(len(keys)); ok {
+		panic(fmt.Sprintf("Expect Select(%d) to fail", len(keys)))
+	}
+}
+
+func testSkipList() {
+	s := NewSkipListWithSeed[int, int](CmpLess[int], 1)
+	checkSkipListKeys(s, []int{})
+	key, val, ok := s.GetLast()
+	checkAVLTreeElement(key, val, ok, 0, 0, false)
+
+	// ranks after inserts and removes, other operations are covered by CheckOrderedMapConformance
+	keys := []int{}
+	for _, k := range rand.Perm(200) {
+		s.Insert(k, -k)
+	}
+	for k := 0; k < 200; k++ {
+		if k%3 == 0 {
+			s.Remove(k)
+		} else {
+			keys = append(keys, k)
+		}
+	}
+	checkSkipListKeys(s, keys)
+	if n := s.CountRange(10, 20); n != 8 {
+		panic(fmt.Sprintf("CountRange(10,20): expect 8, but got %d", n))
+	}
+
+	// the same seed builds the same SkipList
+	a, b := NewSkipListWithSeed[int, int](CmpLess[int], 42), NewSkipListWithSeed[int, int](CmpLess[int], 42)
+	for i := 0; i < 100; i++ {
+		a.Insert(i, -i)
+		b.Insert(i, -i)
+	}
+	for x, y := a.head.Next(), b.head.Next(); x != nil; x, y = x.Next(), y.Next() {
+		if len(x.levels) != len(y.levels) {
+			panic("Expect SkipLists with the same seed to have the same levels")
+		}
+	}
+
+	s.Clear()
+	checkSkipListKeys(s, []int{})
+	s.Insert(1, -1)
+	checkSkipListKeys(s, []int{1})
+}
+
+func testSkipListValidate() {
+	s := NewSkipListWithSeed[int, int](CmpLess[int], 1)
+	for i := 0; i < 50; i++ {
+		s.Insert(i, -i)
+	}
+	if err := s.Validate(); err != nil {
+		panic(err)
+	}
+	s.head.levels[0].span++
+	if s.Validate() == nil {
+		panic("Expect Validate to fail on wrong span")
+	}
+	s.head.levels[0].span--
+	s.head.Next().Key = 100
+	if s.Validate() == nil {
+		panic("Expect Validate to fail on unsorted keys")
+	}
+}
@@ -1 +1,216 @@
-package container
\ No newline at end of file
+package container
+
+import "fmt"
+
+// SortedMap is a map with keys sorted by a given comparator,
+// it is a wrapper of a balanced binary search tree (AVLTree)
+type SortedMap[K, V any] struct {
+	tree *AVLTree[K,V]
+}
+
+// NewSortedMap returns a new SortedMap object given a comparator of key type
+func NewSortedMap[K, V any](cmp func(K,K) int) *SortedMap[K,V] {
+	return &SortedMap[K,V]{
+		tree: NewAVLTree[K,V](cmp),
+	}
+}
+
+// Get returns the value of given key and if the key exists
+// if the keys doesn't exists, returned value is the zero-value of V
+func (m *SortedMap[K,V]) Get(k K) (V, bool) {
+	return m.tree.Get(k)
+}
+
+// MustGet returns the value of the given key if the key exists,
+// otherwise it returns the zero-value of V
+func (m *SortedMap[K,V]) MustGet(k K) V {
+	return m.tree.MustGet(k)
+}
+
+// Has the given key in the SortedMap
+func (m *SortedMap[K,V]) Has(k K) bool {
+	return m.tree.Has(k)
+}
+
+// Put a key-value pair into the SortedMap
+func (m *SortedMap[K,V]) Put(k K, v V) {
+	m.tree.Insert(k, v)
+}
+
+// Delete a key-value pair with given key, returns the value and if the key exists.
+// If the key doesn't exist, returned value is the zero-value of V
+func (m *SortedMap[K,V]) Delete(k K) (v V, ok bool) {
+	if v, ok = m.tree.Get(k); ok {
+		m.tree.Remove(k)
+	}
+	return
+}
+
+// Keys returns all keys of the SortedMap in ascending order
+func (m *SortedMap[K,V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	m.tree.root.inorder(func(node *avlNode[K,V]) bool {
+		keys = append(keys, node.Key)
+		return true
+	})
+	return keys
+}
+
+// Values returns all values of the SortedMap in the ascending order of their keys
+func (m *SortedMap[K,V]) Values() []V {
+	values := make([]V, 0, m.Len())
+	m.tree.root.inorder(func(node *avlNode[K,V]) bool {
+		values = append(values, node.Value)
+		return true
+	})
+	return values
+}
+
+// Entries returns all key-value pairs of the SortedMap in ascending order of keys
+func (m *SortedMap[K,V]) Entries() []Pair[K,V] {
+	entries := make([]Pair[K,V], 0, m.Len())
+	m.tree.root.inorder(func(node *avlNode[K,V]) bool {
+		entries = append(entries, Pair[K,V]{Key: node.Key, Value: node.Value})
+		return true
+	})
+	return entries
+}
+
+// Floor returns the entry less than or equal to the given key if exists
+func (m *SortedMap[K,V]) Floor(k K) (K, V, bool) {
+	return m.tree.GetFloor(k)
+}
+
+// Ceiling returns the entry greater than or equal to the given key if exists
+func (m *SortedMap[K,V]) Ceiling(k K) (K, V, bool) {
+	return m.tree.GetCeiling(k)
+}
+
+// Lower returns the entry less than the given key if exists
+func (m *SortedMap[K,V]) Lower(k K) (K, V, bool) {
+	return m.tree.GetLower(k)
+}
+
+// Higher returns the entry greater than the given key if exists
+func (m *SortedMap[K,V]) Higher(k K) (K, V, bool) {
+	return m.tree.GetHigher(k)
+}
+
+// First returns the entry with the smallest key if exists
+func (m *SortedMap[K,V]) First() (K, V, bool) {
+	return m.tree.GetFirst()
+}
+
+// Last returns the entry with the greatest key if exists
+func (m *SortedMap[K,V]) Last() (K, V, bool) {
+	return m.tree.GetLast()
+}
+
+// PollFirst removes the entry with the smallest key and returns the key-value pair
+// and if the entry exists.
+// The entry doesn't exist if and only if the SortedMap is empty.
+func (m *SortedMap[K,V]) PollFirst() (k K, v V, ok bool) {
+	if k, v, ok = m.tree.GetFirst(); ok {
+		m.tree.Remove(k)
+	}
+	return
+}
+
+// PollLast removes the entry with the greatest key and returns the key-value pair
+// and if the entry exists.
+// The entry doesn't exist if and only if the SortedMap is empty.
+func (m *SortedMap[K,V]) PollLast() (k K, v V, ok bool) {
+	if k, v, ok = m.tree.GetLast(); ok {
+		m.tree.Remove(k)
+	}
+	return
+}
+
+// Clear all elements in the SortedMap
+func (m *SortedMap[K,V]) Clear() {
+	m.tree.Clear()
+}
+
+// Len returns the size of the SortedMap
+func (m *SortedMap[K,V]) Len() int {
+	return m.tree.Len()
+}
+
+/////////////////////////////
+///////// Testing ///////////
+/////////////////////////////
+
+func checkSMapEntries(m *SortedMap[string, int], expKeys []string, expVals []int) {
+	if m.Len() != len(expKeys) {
+		panic(fmt.Sprintf("Len check failed, got %d, expect, %d", m.Len(), len(expKeys)))
+	}
+	keys, values, entries := m.Keys(), m.Values(), m.Entries()
+	if len(keys) != len(expKeys) || len(values) != len(expKeys) || len(entries) != len(expKeys) {
+		panic(fmt.Sprintf("Length of keys, values, entries do not match, got %d,%d,%d, expect %d",
+			len(keys), len(values), len(entries), len(expKeys)))
+	}
+	for i := range expKeys {
+		if keys[i] != expKeys[i] || values[i] != expVals[i] || entries[i].Key != expKeys[i] || entries[i].Value != expVals[i] {
+			panic(fmt.Sprintf("entry %d failed, got (k,v): (%v,%v), expected (%v,%v)", i, keys[i], values[i], expKeys[i], expVals[i]))
+		}
+	}
+}
+
+func checkSMapKV(gotK string, gotV int, gotOk bool, expK string, expV int, expOk bool) {
+	if gotK != expK || gotV != expV || gotOk != expOk {
+		panic(fmt.Sprintf("k,v,ok does not match where key:(%v,%v), val:(%v,%v), ok:(%v,%v)",
+			gotK, expK, gotV, expV, gotOk, expOk))
+	}
+}
+
+func testSortedMap() {
+	var k string
+	var v int
+	var ok bool
+
+	m := NewSortedMap[string, int](CmpLess[string])
+	checkSMapEntries(m, []string{}, []int{})
+	k, v, ok = m.First()
+	checkSMapKV(k, v, ok, "", 0, false)
+	k, v, ok = m.PollLast()
+	checkSMapKV(k, v, ok, "", 0, false)
+
+	// single element
+	m.Put("banana", 2)
+	checkSMapEntries(m, []string{"banana"}, []int{2})
+	v, ok = m.Get("banana")
+	checkSMapKV(k, v, ok, k, 2, true)
+	m.Put("banana", 3)
+	checkSMapEntries(m, []string{"banana"}, []int{3})
+	v, ok = m.Delete("banana")
+	checkSMapKV(k, v, ok, k, 3, true)
+	v, ok = m.Delete("banana")
+	checkSMapKV(k, v, ok, k, 0, false)
+	checkSMapEntries(m, []string{}, []int{})
+
+	// multiple elements
+	m.Put("cherry", 3)
+	m.Put("apple", 1)
+	m.Put("durian", 4)
+	m.Put("banana", 2)
+	checkSMapEntries(m, []string{"apple", "banana", "cherry", "durian"}, []int{1, 2, 3, 4})
+	k, v, ok = m.Floor("c")
+	checkSMapKV(k, v, ok, "banana", 2, true)
+	k, v, ok = m.Ceiling("c")
+	checkSMapKV(k, v, ok, "cherry", 3, true)
+	k, v, ok = m.Lower("apple")
+	checkSMapKV(k, v, ok, "", 0, false)
+	k, v, ok = m.Higher("cherry")
+	checkSMapKV(k, v, ok, "durian", 4, true)
+	k, v, ok = m.First()
+	checkSMapKV(k, v, ok, "apple", 1, true)
+	k, v, ok = m.Last()
+	checkSMapKV(k, v, ok, "durian", 4, true)
+	k, v, ok = m.PollFirst()
+	checkSMapKV(k, v, ok, "apple", 1, true)
+	k, v, ok = m.PollLast()
+	checkSMapKV(k, v, ok, "durian", 4, true)
+	checkSMapEntries(m, []string{"banana", "cherry"}, []int{2, 3})
+	m.Clear()
+	checkSMapEntries(m, []string{}, []int{})
+}
@@ -0,0 +1,252 @@
+package container
+
+import "fmt"
+
+// SortedSet is a set with keys sorted by a given comparator
+type SortedSet[K any] SortedMap[K, struct{}]
+
+// NewSortedSet returns a new SortedSet object given a comparator of key type
+func NewSortedSet[K any](cmp func(K,K) int) *SortedSet[K] {
+	m := NewSortedMap[K, struct{}](cmp)
+	return (*SortedSet[K])(m)
+}
+
+// Has the given key in the SortedSet
+func (s *SortedSet[K]) Has(k K) bool {
+	return s.tree.Has(k)
+}
+
+// Insert a key into the SortedSet
+func (s *SortedSet[K]) Insert(k K) {
+	s.tree.Insert(k, struct{}{})
+}
+
+// Remove the given key, returns if the key exists.
+func (s *SortedSet[K]) Remove(k K) bool {
+	m := (*SortedMap[K, struct{}])(s)
+	_, ok := m.Delete(k)
+	return ok
+}
+
+// Keys returns all keys of the SortedSet in ascending order
+func (s *SortedSet[K]) Keys() []K {
+	m := (*SortedMap[K, struct{}])(s)
+	return m.Keys()
+}
+
+// Floor returns the key less than or equal to the given key if exists
+func (s *SortedSet[K]) Floor(k K) (rKey K, ok bool) {
+	rKey, _, ok = s.tree.GetFloor(k)
+	return
+}
+
+// Ceiling returns the key greater than or equal to the given key if exists
+func (s *SortedSet[K]) Ceiling(k K) (rKey K, ok bool) {
+	rKey, _, ok = s.tree.GetCeiling(k)
+	return
+}
+
+// Lower returns the key less than the given key if exists
+func (s *SortedSet[K]) Lower(k K) (rKey K, ok bool) {
+	rKey, _, ok = s.tree.GetLower(k)
+	return
+}
+
+// Higher returns the key greater than the given key if exists
+func (s *SortedSet[K]) Higher(k K) (rKey K, ok bool) {
+	rKey, _, ok = s.tree.GetHigher(k)
+	return
+}
+
+// First returns the smallest key if exists
+func (s *SortedSet[K]) First() (k K, ok bool) {
+	k, _, ok = s.tree.GetFirst()
+	return
+}
+
+// Last returns the greatest key if exists
+func (s *SortedSet[K]) Last() (k K, ok bool) {
+	k, _, ok = s.tree.GetLast()
+	return
+}
+
+// PollFirst removes the smallest key and returns the key and if the key exists.
+// The key doesn't exist if and only if the SortedSet is empty.
+func (s *SortedSet[K]) PollFirst() (k K, ok bool) {
+	m := (*SortedMap[K, struct{}])(s)
+	k, _, ok = m.PollFirst()
+	return
+}
+
+// PollLast removes the greatest key and returns the key and if the key exists.
+// The key doesn't exist if and only if the SortedSet is empty.
+func (s *SortedSet[K]) PollLast() (k K, ok bool) {
+	m := (*SortedMap[K, struct{}])(s)
+	k, _, ok = m.PollLast()
+	return
+}
+
+// Union returns a new SortedSet with keys in either s or other.
+// Both sets must be sorted by the same comparator, which is the one
+// used by the returned SortedSet.
+func (s *SortedSet[K]) Union(other *SortedSet[K]) *SortedSet[K] {
+	return s.merge(other, true, true, true)
+}
+
+// Intersection returns a new SortedSet with keys in both s and other.
+// Both sets must be sorted by the same comparator, which is the one
+// used by the returned SortedSet.
+func (s *SortedSet[K]) Intersection(other *SortedSet[K]) *SortedSet[K] {
+	return s.merge(other, false, true, false)
+}
+
+// Difference returns a new SortedSet with keys in s but not in other.
+// Both sets must be sorted by the same comparator, which is the one
+// used by the returned SortedSet.
+func (s *SortedSet[K]) Difference(other *SortedSet[K]) *SortedSet[K] {
+	return s.merge(other, true, false, false)
+}
+
+// merge walks both sets in order and keeps the keys only in s, in both sets,
+// or only in other according to the flags. The result is built in linear time.
+func (s *SortedSet[K]) merge(other *SortedSet[K], onlyS, both, onlyOther bool) *SortedSet[K] {
+	cmp := s.tree.cmp
+	xs, ys := s.Keys(), other.Keys()
+	entries := make([]Pair[K, struct{}], 0, len(xs)+len(ys))
+	i, j := 0, 0
+	for i < len(xs) || j < len(ys) {
+		c := 0
+		if i == len(xs) {
+			c = 1
+		} else if j == len(ys) {
+			c = -1
+		} else {
+			c = cmp(xs[i], ys[j])
+		}
+
+		if c < 0 {
+			if onlyS {
+				entries = append(entries, Pair[K, struct{}]{Key: xs[i]})
+			}
+			i++
+		} else if c > 0 {
+			if onlyOther {
+				entries = append(entries, Pair[K, struct{}]{Key: ys[j]})
+			}
+			j++
+		} else {
+			if both {
+				entries = append(entries, Pair[K, struct{}]{Key: xs[i]})
+			}
+			i, j = i+1, j+1
+		}
+	}
+
+	res := NewSortedSet[K](cmp)
+	res.tree.root = buildAVLNodes[K, struct{}](entries)
+	return res
+}
+
+// Clear all elements in the SortedSet
+func (s *SortedSet[K]) Clear() {
+	s.tree.Clear()
+}
+
+// Len returns the size of the SortedSet
+func (s *SortedSet[K]) Len() int {
+	return s.tree.Len()
+}
+
+/////////////////////////////
+///////// Testing ///////////
+/////////////////////////////
+
+func checkSSetKeys(s *SortedSet[int], expect []int) {
+	if s.Len() != len(expect) {
+		panic(fmt.Sprintf("Len check failed, got %d, expect, %d", s.Len(), len(expect)))
+	}
+	keys := s.Keys()
+	for i := range expect {
+		if keys[i] != expect[i] {
+			panic(fmt.Sprintf("key %d failed, got %v, expected %v", i, keys[i], expect[i]))
+		}
+	}
+}
+
+func checkSSetK(gotK int, gotOk bool, expK int, expOk bool) {
+	if gotK != expK || gotOk != expOk {
+		panic(fmt.Sprintf("k,ok does not match where key:(%v,%v), ok:(%v,%v)",
+			gotK, expK, gotOk, expOk))
+	}
+}
+
+func newSSet(keys ...int) *SortedSet[int] {
+	s := NewSortedSet[int](CmpLess[int])
+	for _, k := range keys {
+		s.Insert(k)
+	}
+	return s
+}
+
+func testSortedSet() {
+	var k int
+	var ok bool
+
+	s := newSSet()
+	checkSSetKeys(s, []int{})
+	k, ok = s.PollFirst()
+	checkSSetK(k, ok, 0, false)
+
+	// single element
+	s.Insert(1)
+	s.Insert(1)
+	checkSSetKeys(s, []int{1})
+	ok = s.Remove(1)
+	checkSSetK(k, ok, k, true)
+	ok = s.Remove(1)
+	checkSSetK(k, ok, k, false)
+
+	// navigation
+	s = newSSet(5, 1, 9, 3, 7)
+	checkSSetKeys(s, []int{1, 3, 5, 7, 9})
+	k, ok = s.Floor(4)
+	checkSSetK(k, ok, 3, true)
+	k, ok = s.Ceiling(4)
+	checkSSetK(k, ok, 5, true)
+	k, ok = s.Lower(1)
+	checkSSetK(k, ok, 0, false)
+	k, ok = s.Higher(7)
+	checkSSetK(k, ok, 9, true)
+	k, ok = s.First()
+	checkSSetK(k, ok, 1, true)
+	k, ok = s.Last()
+	checkSSetK(k, ok, 9, true)
+	k, ok = s.PollFirst()
+	checkSSetK(k, ok, 1, true)
+	k, ok = s.PollLast()
+	checkSSetK(k, ok, 9, true)
+	checkSSetKeys(s, []int{3, 5, 7})
+
+	// set algebra
+	a, b := newSSet(1, 2, 3, 5, 8), newSSet(2, 3, 4, 8, 9)
+	checkSSetKeys(a.Union(b), []int{1, 2, 3, 4, 5, 8, 9})
+	checkSSetKeys(a.Intersection(b), []int{2, 3, 8})
+	checkSSetKeys(a.Difference(b), []int{1, 5})
+	checkSSetKeys(b.Difference(a), []int{4, 9})
+	checkSSetKeys(a.Union(newSSet()), []int{1, 2, 3, 5, 8})
+	checkSSetKeys(a.Intersection(newSSet()), []int{})
+	if err := a.Union(b).tree.Validate(); err != nil {
+		panic(err)
+	}
+	checkSSetKeys(a, []int{1, 2, 3, 5, 8})
+	checkSSetKeys(b, []int{2, 3, 4, 8, 9})
+
+	// result is a usable set
+	u := a.Union(b)
+	u.Insert(6)
+	u.Remove(1)
+	checkSSetKeys(u, []int{2, 3, 4, 5, 6, 8, 9})
+
+	s.Clear()
+	checkSSetKeys(s, []int{})
+}
@@ -2,15 +2,16 @@ package container
 
 import "fmt"
 
-// Stack is an implementation of Stack
+// Stack is an implementation of Stack.
+// The zero value is an empty Stack ready to use.
+// A Stack must not be copied after first use.
 type Stack[T any] struct {
-	list *List[T] // hide all methods of List
+	list List[T] // hide all methods of List
 }
 
 // NewStack returns a new Stack object
 func NewStack[T any]() *Stack[T] {
-	l := NewList[T]()
-	return &Stack[T]{l}
+	return &Stack[T]{}
 }
 
 // Push a new element to the Stack
@@ -81,4 +82,18 @@ func testStack() {
 
 	s.Clear()
 	checkStackSize(s, 0)
-}
\ No newline at end of file
+}
+
+func testStackZeroValue() {
+	var holder struct {
+		s Stack[int]
+	}
+	s := &holder.s
+	checkStackSize(s, 0)
+	s.Clear()
+	s.Push(1)
+	s.Push(2)
+	checkStackSize(s, 2)
+	checkStackNum(s.Pop(), 2)
+	checkStackNum(s.Top(), 1)
+}
@@ -0,0 +1,853 @@
+package container
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// treapNode is the Treap node structure
+type treapNode[K, V any] struct {
+	Key K
+	Value V
+	Left *treapNode[K,V]
+	Right *treapNode[K,V]
+	priority uint64
+	size int
+}
+
+// Size of a treapNode including self. nil node returns 0
+func (node *treapNode[K,V]) Size() int {
+	if node == nil {
+		return 0
+	}
+	return node.size
+}
+
+// Maintain the size of a treapNode. nil node has no-op
+func (node *treapNode[K,V]) Maintain() {
+	if node == nil {
+		return
+	}
+	node.size = 1 + node.Left.Size() + node.Right.Size()
+}
+
+// entry of a treapNode, see bstNode
+func (node *treapNode[K,V]) entry() (K, V) {
+	return node.Key, node.Value
+}
+
+// children of a treapNode, see bstNode
+func (node *treapNode[K,V]) children() (*treapNode[K,V], *treapNode[K,V]) {
+	return node.Left, node.Right
+}
+
+// Treap is a randomized binary search tree with no duplicated keys, which is a binary
+// search tree by keys and a max-heap by random priorities of nodes. All updates are
+// built on split and merge, which take O(log n) expected time.
+type Treap[K, V any] struct {
+	root *treapNode[K,V]
+	cmp func(K,K) int
+	rng *rand.Rand
+}
+
+// NewTreap creates a new Treap given a comparator of key type,
+// priorities are drawn from a randomly seeded source
+func NewTreap[K,V any](cmp func(K,K) int) *Treap[K,V] {
+	return NewTreapWithSeed[K,V](cmp, rand.Int63())
+}
+
+// NewTreapWithSeed creates a new Treap given a comparator of key type and the seed
+// of priorities, such that the same operations build the same Treap for reproducibility
+func NewTreapWithSeed[K,V any](cmp func(K,K) int, seed int64) *Treap[K,V] {
+	return &Treap[K,V]{
+		cmp: cmp,
+		rng: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Has key in the Treap
+func (t *Treap[K,V]) Has(key K) bool {
+	_, ok := t.Get(key)
+	return ok
+}
+
+// MustGet returns value of given key if the key exists, otherwise
+// returns zero-value of V
+func (t *Treap[K,V]) MustGet(key K) (rVal V) {
+	if v, ok := t.Get(key); ok {
+		rVal = v
+	}
+	return
+}
+
+// Get key from Treap, return value and whether the key is found
+// if not found, return value is the zero-value of type V
+func (t *Treap[K,V]) Get(key K) (rVal V, ok bool) {
+	if node := t.find(key); node != nil {
+		rVal, ok = node.Value, true
+	}
+	return
+}
+
+// find the node with the given key, returns nil if not exists
+func (t *Treap[K,V]) find(key K) *treapNode[K,V] {
+	node := t.root
+	for node != nil {
+		cmp := t.cmp(node.Key, key)
+		if cmp == 0 {
+			return node
+		}
+
+		if cmp > 0 {
+			node = node.Left
+		} else {
+			node = node.Right
+		}
+	}
+	return nil
+}
+
+// GetFloor returns the entry less than or equal to the given key if exists
+func (t *Treap[K,V]) GetFloor(key K) (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		cmp := t.cmp(node.Key, key)
+		if cmp == 0 {
+			rKey, rVal, ok = node.Key, node.Value, true
+			return
+		}
+
+		if cmp > 0 {
+			node = node.Left
+		} else {
+			rKey, rVal, ok = node.Key, node.Value, true
+			node = node.Right
+		}
+	}
+	return
+}
+
+// GetCeiling returns the entry greater than or equal to the given key if exists
+func (t *Treap[K,V]) GetCeiling(key K) (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		cmp := t.cmp(node.Key, key)
+		if cmp == 0 {
+			rKey, rVal, ok = node.Key, node.Value, true
+			return
+		}
+
+		if cmp > 0 {
+			rKey, rVal, ok = node.Key, node.Value, true
+			node = node.Left
+		} else {
+			node = node.Right
+		}
+	}
+	return
+}
+
+// GetLower returns the entry less than the given key if exists
+func (t *Treap[K,V]) GetLower(key K) (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		if t.cmp(node.Key, key) >= 0 {
+			node = node.Left
+		} else {
+			rKey, rVal, ok = node.Key, node.Value, true
+			node = node.Right
+		}
+	}
+	return
+}
+
+// GetHigher returns the entry greater than the given key if exists
+func (t *Treap[K,V]) GetHigher(key K) (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		if t.cmp(node.Key, key) <= 0 {
+			node = node.Right
+		} else {
+			rKey, rVal, ok = node.Key, node.Value, true
+			node = node.Left
+		}
+	}
+	return
+}
+
+// GetFirst returns the smallest element (according to cmp) in the Treap if exists
+func (t *Treap[K,V]) GetFirst() (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		rKey, rVal, ok = node.Key, node.Value, true
+		node = node.Left
+	}
+	return
+}
+
+// GetLast returns the greatest element (according to cmp) in the Treap if exists
+func (t *Treap[K,V]) GetLast() (rKey K, rVal V, ok bool) {
+	node := t.root
+	for node != nil {
+		rKey, rVal, ok = node.Key, node.Value, true
+		node = node.Right
+	}
+	return
+}
+
+// Len return the size of the Treap
+func (t *Treap[K,V]) Len() int {
+	return t.root.Size()
+}
+
+// Rank returns the number of keys strictly less than the given key
+func (t *Treap[K,V]) Rank(key K) int {
+	rank, node := 0, t.root
+	for node != nil {
+		if t.cmp(node.Key, key) < 0 {
+			rank += node.Left.Size() + 1
+			node = node.Right
+		} else {
+			node = node.Left
+		}
+	}
+	return rank
+}
+
+// Select returns the i-th (0-indexed) smallest entry (according to cmp) if exists,
+// the entry exists if and only if 0 <= i < t.Len()
+func (t *Treap[K,V]) Select(i int) (rKey K, rVal V, ok bool) {
+	if i < 0 || i >= t.Len() {
+		return
+	}
+	node := t.root
+	for node != nil {
+		lsize := node.Left.Size()
+		if i == lsize {
+			rKey, rVal, ok = node.Key, node.Value, true
+			return
+		}
+
+		if i < lsize {
+			node = node.Left
+		} else {
+			i -= lsize + 1
+			node = node.Right
+		}
+	}
+	return
+}
+
+// Each calls fn on each entry in ascending order of keys. It stops once fn returns false.
+func (t *Treap[K,V]) Each(fn func(K,V) bool) {
+	eachNode[*treapNode[K,V],K,V](t.root, fn)
+}
+
+// Range calls fn on each entry with key between lo and hi in ascending order,
+// whether lo and hi are included is decided by loInclusive and hiInclusive.
+// It stops once fn returns false.
+func (t *Treap[K,V]) Range(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool) {
+	rangeNode[*treapNode[K,V],K,V](t.root, t.cmp, lo, hi, loInclusive, hiInclusive, false, fn)
+}
+
+// RangeReverse is the same as Range but visits entries in descending order
+func (t *Treap[K,V]) RangeReverse(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool) {
+	rangeNode[*treapNode[K,V],K,V](t.root, t.cmp, lo, hi, loInclusive, hiInclusive, true, fn)
+}
+
+// Insert a key-value pair into the Treap
+func (t *Treap[K,V]) Insert(key K, value V) {
+	if node := t.find(key); node != nil {
+		node.Value = value
+		return
+	}
+
+	node := &treapNode[K,V]{
+		Key: key,
+		Value: value,
+		priority: t.rng.Uint64(),
+		size: 1,
+	}
+	left, right := t.split(t.root, key, false)
+	t.root = t.merge(t.merge(left, node), right)
+}
+
+// Remove the node with given key in the Treap if exists
+// it has no-op if key is not in the tree
+func (t *Treap[K,V]) Remove(key K) {
+	left, right := t.split(t.root, key, false)
+	_, right = t.split(right, key, true)
+	t.root = t.merge(left, right)
+}
+
+// Split the Treap into two Treaps with the same comparator, where left contains
+// keys less than the given key and right contains keys greater than or equal to it.
+// All entries are moved to the new Treaps, such that the Treap becomes empty.
+func (t *Treap[K,V]) Split(key K) (left, right *Treap[K,V]) {
+	left = NewTreapWithSeed[K,V](t.cmp, t.rng.Int63())
+	right = NewTreapWithSeed[K,V](t.cmp, t.rng.Int63())
+	left.root, right.root = t.split(t.root, key, false)
+	t.root = nil
+	return
+}
+
+// Merge moves all entries of other into the Treap, such that other becomes empty.
+// Keys of other must be all greater than or all less than keys in the Treap,
+// otherwise it panics.
+func (t *Treap[K,V]) Merge(other *Treap[K,V]) {
+	if other.root == nil {
+		return
+	}
+	if t.root == nil {
+		t.root, other.root = other.root, nil
+		return
+	}
+
+	tFirst, _, _ := t.GetFirst()
+	tLast, _, _ := t.GetLast()
+	otherFirst, _, _ := other.GetFirst()
+	otherLast, _, _ := other.GetLast()
+	if t.cmp(tLast, otherFirst) < 0 {
+		t.root = t.merge(t.root, other.root)
+	} else if t.cmp(otherLast, tFirst) < 0 {
+		t.root = t.merge(other.root, t.root)
+	} else {
+		panic("Cannot merge Treaps with overlapping keys")
+	}
+	other.root = nil
+}
+
+// split the subtree of node into keys less than key (less than or equal to key
+// if inclusive) and the rest
+func (t *Treap[K,V]) split(node *treapNode[K,V], key K, inclusive bool) (left, right *treapNode[K,V]) {
+	if node == nil {
+		return
+	}
+
+	if cmp := t.cmp(node.Key, key); cmp < 0 || (inclusive && cmp == 0) {
+		node.Right, right = t.split(node.Right, key, inclusive)
+		left = node
+	} else {
+		left, node.Left = t.split(node.Left, key, inclusive)
+		right = node
+	}
+	node.Maintain()
+	return
+}
+
+// merge two subtrees, where keys in left are less than keys in right
+func (t *Treap[K,V]) merge(left, right *treapNode[K,V]) *treapNode[K,V] {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+
+	if left.priority >= right.priority {
+		left.Right = t.merge(left.Right, right)
+		left.Maintain()
+		return left
+	}
+	right.Left = t.merge(left, right.Left)
+	right.Maintain()
+	return right
+}
+
+// Clear all element in the Treap
+func (t *Treap[K,V]) Clear() {
+	t.root = nil
+}
+
+// Validate checks keys are strictly increasing (according to cmp), priorities and sizes
+// of nodes are correct, returns an error at the first offending key
+func (t *Treap[K,V]) Validate() error {
+	return t.validate(t.root, nil, nil)
+}
+
+// recursively validate the subtree, where keys must be between lo and hi if not nil
+func (t *Treap[K,V]) validate(node, lo, hi *treapNode[K,V]) error {
+	if node == nil {
+		return nil
+	}
+
+	if t.cmp(node.Key, node.Key) != 0 {
+		return fmt.Errorf("key %v is not equal to itself", node.Key)
+	}
+	if lo != nil && (t.cmp(lo.Key, node.Key) >= 0 || t.cmp(node.Key, lo.Key) <= 0) {
+		return fmt.Errorf("key %v is not greater than %v", node.Key, lo.Key)
+	}
+	if hi != nil && (t.cmp(node.Key, hi.Key) >= 0 || t.cmp(hi.Key, node.Key) <= 0) {
+		return fmt.Errorf("key %v is not less than %v", node.Key, hi.Key)
+	}
+	for _, child := range []*treapNode[K,V]{node.Left, node.Right} {
+		if child != nil && child.priority > node.priority {
+			return fmt.Errorf("key %v has priority less than its child %v", node.Key, child.Key)
+		}
+	}
+	if size := 1 + node.Left.Size() + node.Right.Size(); node.size != size {
+		return fmt.Errorf("key %v has size %d, expect %d", node.Key, node.size, size)
+	}
+
+	if err := t.validate(node.Left, lo, node); err != nil {
+		return err
+	}
+	return t.validate(node.Right, node, hi)
+}
+
+// implicitTreapNode is the ImplicitTreap node structure
+type implicitTreapNode[T any] struct {
+	Value T
+	Left *implicitTreapNode[T]
+	Right *implicitTreapNode[T]
+	priority uint64
+	size int
+	reversed bool // the subtree is to be reversed, excluding the node itself
+}
+
+// Size of a implicitTreapNode including self. nil node returns 0
+func (node *implicitTreapNode[T]) Size() int {
+	if node == nil {
+		return 0
+	}
+	return node.size
+}
+
+// Maintain the size of a implicitTreapNode. nil node has no-op
+func (node *implicitTreapNode[T]) Maintain() {
+	if node == nil {
+		return
+	}
+	node.size = 1 + node.Left.Size() + node.Right.Size()
+}
+
+// push the pending reversal down to the children. nil node has no-op
+func (node *implicitTreapNode[T]) push() {
+	if node == nil || !node.reversed {
+		return
+	}
+	node.Left, node.Right = node.Right, node.Left
+	for _, child := range []*implicitTreapNode[T]{node.Left, node.Right} {
+		if child != nil {
+			child.reversed = !child.reversed
+		}
+	}
+	node.reversed = false
+}
+
+// ImplicitTreap is a sequence backed by a Treap keyed by the index of elements
+// (implicit key), which supports inserting, removing, reversing, splitting and
+// merging at any position in O(log n) expected time.
+type ImplicitTreap[T any] struct {
+	root *implicitTreapNode[T]
+	rng *rand.Rand
+}
+
+// NewImplicitTreap creates a new ImplicitTreap, priorities are drawn from
+// a randomly seeded source
+func NewImplicitTreap[T any]() *ImplicitTreap[T] {
+	return NewImplicitTreapWithSeed[T](rand.Int63())
+}
+
+// NewImplicitTreapWithSeed creates a new ImplicitTreap given the seed of priorities,
+// such that the same operations build the same ImplicitTreap for reproducibility
+func NewImplicitTreapWithSeed[T any](seed int64) *ImplicitTreap[T] {
+	return &ImplicitTreap[T]{
+		rng: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Len returns the number of elements in the ImplicitTreap
+func (t *ImplicitTreap[T]) Len() int {
+	return t.root.Size()
+}
+
+// Get the ith element of the ImplicitTreap
+// index i must be satisfied 0 <= i < t.Len()
+func (t *ImplicitTreap[T]) Get(i int) T {
+	return t.at(i).Value
+}
+
+// Set the ith element of the ImplicitTreap
+// index i must be satisfied 0 <= i < t.Len()
+func (t *ImplicitTreap[T]) Set(i int, x T) {
+	t.at(i).Value = x
+}
+
+// find the node of the ith element, pending reversals on the path are pushed down
+func (t *ImplicitTreap[T]) at(i int) *implicitTreapNode[T] {
+	t.checkIndex(i, t.Len()-1)
+	node := t.root
+	for {
+		node.push()
+		lsize := node.Left.Size()
+		if i == lsize {
+			return node
+		}
+
+		if i < lsize {
+			node = node.Left
+		} else {
+			i -= lsize + 1
+			node = node.Right
+		}
+	}
+}
+
+// Insert a new element at the given index i
+// index i must be satisfied 0 <= i <= t.Len()
+func (t *ImplicitTreap[T]) Insert(i int, x T) {
+	t.checkIndex(i, t.Len())
+	node := &implicitTreapNode[T]{
+		Value: x,
+		priority: t.rng.Uint64(),
+		size: 1,
+	}
+	left, right := t.split(t.root, i)
+	t.root = t.merge(t.merge(left, node), right)
+}
+
+// Remove the element at given index
+// index i must be satisfied 0 <= i < t.Len()
+func (t *ImplicitTreap[T]) Remove(i int) T {
+	t.checkIndex(i, t.Len()-1)
+	left, right := t.split(t.root, i)
+	mid, right := t.split(right, 1)
+	t.root = t.merge(left, right)
+	return mid.Value
+}
+
+// Reverse the elements with index in [from, to)
+// the range must be satisfied 0 <= from <= to <= t.Len()
+func (t *ImplicitTreap[T]) Reverse(from, to int) {
+	t.checkIndex(to, t.Len())
+	t.checkIndex(from, to)
+	left, right := t.split(t.root, to)
+	left, mid := t.split(left, from)
+	if mid != nil {
+		mid.reversed = !mid.reversed
+	}
+	t.root = t.merge(t.merge(left, mid), right)
+}
+
+// Split the ImplicitTreap into two ImplicitTreaps, where left contains the first i
+// elements and right contains the rest. All elements are moved to the new
+// ImplicitTreaps, such that the ImplicitTreap becomes empty.
+// index i must be satisfied 0 <= i <= t.Len()
+func (t *ImplicitTreap[T]) Split(i int) (left, right *ImplicitTreap[T]) {
+	t.checkIndex(i, t.Len())
+	left = NewImplicitTreapWithSeed[T](t.rng.Int63())
+	right = NewImplicitTreapWithSeed[T](t.rng.Int63())
+	left.root, right.root = t.split(t.root, i)
+	t.root = nil
+	return
+}
+
+// Merge appends all elements of other to the ImplicitTreap, such that other becomes empty.
+// other must not be the ImplicitTreap itself.
+func (t *ImplicitTreap[T]) Merge(other *ImplicitTreap[T]) {
+	if other == t {
+		panic("Cannot merge an ImplicitTreap with itself")
+	}
+	t.root = t.merge(t.root, other.root)
+	other.root = nil
+}
+
+// Values returns all elements of the ImplicitTreap in order
+func (t *ImplicitTreap[T]) Values() []T {
+	values := make([]T, 0, t.Len())
+	var walk func(node *implicitTreapNode[T])
+	walk = func(node *implicitTreapNode[T]) {
+		if node == nil {
+			return
+		}
+		node.push()
+		walk(node.Left)
+		values = append(values, node.Value)
+		walk(node.Right)
+	}
+	walk(t.root)
+	return values
+}
+
+// Clear all elements in the ImplicitTreap
+func (t *ImplicitTreap[T]) Clear() {
+	t.root = nil
+}
+
+// Validate checks priorities and sizes of nodes without pushing pending reversals,
+// returns an error at the first offending index
+func (t *ImplicitTreap[T]) Validate() error {
+	_, err := t.validate(t.root, 0, false)
+	return err
+}
+
+// recursively validate the subtree whose first element is at the given index,
+// where reversed is the parity of pending reversals of the ancestors.
+// returns the size of the subtree
+func (t *ImplicitTreap[T]) validate(node *implicitTreapNode[T], index int, reversed bool) (int, error) {
+	if node == nil {
+		return 0, nil
+	}
+
+	reversed = reversed != node.reversed
+	first, second := node.Left, node.Right
+	if reversed {
+		first, second = second, first
+	}
+	lsize, err := t.validate(first, index, reversed)
+	if err != nil {
+		return 0, err
+	}
+	index += lsize
+	rsize, err := t.validate(second, index+1, reversed)
+	if err != nil {
+		return 0, err
+	}
+	for _, child := range []*implicitTreapNode[T]{node.Left, node.Right} {
+		if child != nil && child.priority > node.priority {
+			return 0, fmt.Errorf("element at index %d has priority less than its child", index)
+		}
+	}
+	if node.size != 1+lsize+rsize {
+		return 0, fmt.Errorf("element at index %d has size %d, expect %d", index, node.size, 1+lsize+rsize)
+	}
+	return node.size, nil
+}
+
+// checkIndex panics if i is not in [0, hi]
+func (t *ImplicitTreap[T]) checkIndex(i, hi int) {
+	if i < 0 || i > hi {
+		panic(fmt.Sprintf("index %d out of range [0, %d]", i, hi))
+	}
+}
+
+// split the subtree of node into the first i elements and the rest
+func (t *ImplicitTreap[T]) split(node *implicitTreapNode[T], i int) (left, right *implicitTreapNode[T]) {
+	if node == nil {
+		return
+	}
+
+	node.push()
+	if lsize := node.Left.Size(); lsize < i {
+		node.Right, right = t.split(node.Right, i-lsize-1)
+		left = node
+	} else {
+		left, node.Left = t.split(node.Left, i)
+		right = node
+	}
+	node.Maintain()
+	return
+}
+
+// merge two subtrees, where elements in left come before elements in right
+func (t *ImplicitTreap[T]) merge(left, right *implicitTreapNode[T]) *implicitTreapNode[T] {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+
+	if left.priority >= right.priority {
+		left.push()
+		left.Right = t.merge(left.Right, right)
+		left.Maintain()
+		return left
+	}
+	right.push()
+	right.Left = t.merge(left, right.Left)
+	right.Maintain()
+	return right
+}
+
+/////////////////////////////
+///////// Testing ///////////
+/////////////////////////////
+
+func checkTreapKeys(t *Treap[int, int], keys []int) {
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+	if t.Len() != len(keys) {
+		panic(fmt.Sprintf("Expect size %d, but got %d", len(keys), t.Len()))
+	}
+	for i, k := range keys {
+		if key, val, ok := t.Select(i); !ok || key != k || val != -k {
+			panic(fmt.Sprintf("Select(%d): expect %d,%d, but got %d,%d,%t", i, k, -k, key, val, ok))
+		}
+		if rank := t.Rank(k); rank != i {
+			panic(fmt.Sprintf("Rank(%d): expect %d, but got %d", k, i, rank))
+		}
+	}
+}
+
+func testTreap() {
+	t := NewTreapWithSeed[int, int](CmpLess[int], 1)
+	checkTreapKeys(t, []int{})
+
+	// ranks after inserts and removes, other operations are covered by CheckOrderedMapConformance
+	keys := []int{}
+	for _, k := range rand.Perm(100) {
+		t.Insert(k, -k)
+	}
+	for k := 0; k < 100; k++ {
+		if k%3 == 0 {
+			t.Remove(k)
+		} else {
+			keys = append(keys, k)
+		}
+	}
+	checkTreapKeys(t, keys)
+
+	t = NewTreapWithSeed[int, int](CmpLess[int], 2)
+	for _, k := range []int{5, 1, 9, 3, 7} {
+		t.Insert(k, -k)
+	}
+	checkTreapKeys(t, []int{1, 3, 5, 7, 9})
+
+	// split and merge
+	left, right := t.Split(5)
+	checkTreapKeys(t, []int{})
+	checkTreapKeys(left, []int{1, 3})
+	checkTreapKeys(right, []int{5, 7, 9})
+	right.Merge(left)
+	checkTreapKeys(right, []int{1, 3, 5, 7, 9})
+	checkTreapKeys(left, []int{})
+	func() {
+		defer func() {
+			if recover() == nil {
+				panic("Expect Merge to panic on overlapping keys")
+			}
+		}()
+		other := NewTreap[int, int](CmpLess[int])
+		other.Insert(4, -4)
+		right.Merge(other)
+	}()
+
+	// the same seed builds the same Treap
+	a, b := NewTreapWithSeed[int, int](CmpLess[int], 42), NewTreapWithSeed[int, int](CmpLess[int], 42)
+	for i := 0; i < 100; i++ {
+		a.Insert(i, -i)
+		b.Insert(i, -i)
+	}
+	for i := 0; i < 100; i++ {
+		na, nb := a.find(i), b.find(i)
+		if na.priority != nb.priority || na.Left.Size() != nb.Left.Size() {
+			panic("Expect Treaps with the same seed to have the same shape")
+		}
+	}
+
+	t.Clear()
+	checkTreapKeys(t, []int{})
+}
+
+func checkImplicitTreap(t *ImplicitTreap[int], expect []int) {
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+	if t.Len() != len(expect) {
+		panic(fmt.Sprintf("Expect size %d, but got %d", len(expect), t.Len()))
+	}
+	values := t.Values()
+	for i := range expect {
+		if values[i] != expect[i] || t.Get(i) != expect[i] {
+			panic(fmt.Sprintf("Expect %v, but got %v", expect, values))
+		}
+	}
+}
+
+func testImplicitTreap() {
+	t := NewImplicitTreapWithSeed[int](1)
+	checkImplicitTreap(t, []int{})
+
+	// random edits against a slice
+	expect := []int{}
+	for i := 0; i < 500; i++ {
+		switch op := rand.Intn(4); {
+		case op < 2 || len(expect) == 0:
+			pos := rand.Intn(len(expect) + 1)
+			t.Insert(pos, i)
+			expect = append(expect[:pos], append([]int{i}, expect[pos:]...)...)
+		case op == 2:
+			pos := rand.Intn(len(expect))
+			if x := t.Remove(pos); x != expect[pos] {
+				panic(fmt.Sprintf("Remove(%d): expect %d, but got %d", pos, expect[pos], x))
+			}
+			expect = append(expect[:pos], expect[pos+1:]...)
+		default:
+			from := rand.Intn(len(expect) + 1)
+			to := from + rand.Intn(len(expect)-from+1)
+			t.Reverse(from, to)
+			for l, r := from, to-1; l < r; l, r = l+1, r-1 {
+				expect[l], expect[r] = expect[r], expect[l]
+			}
+		}
+	}
+	checkImplicitTreap(t, expect)
+
+	t = NewImplicitTreapWithSeed[int](2)
+	for i := 0; i < 10; i++ {
+		t.Insert(i, i)
+	}
+	t.Reverse(2, 8)
+	t.Set(0, 10)
+	checkImplicitTreap(t, []int{10, 1, 7, 6, 5, 4, 3, 2, 8, 9})
+
+	// split and merge
+	left, right := t.Split(3)
+	checkImplicitTreap(t, []int{})
+	checkImplicitTreap(left, []int{10, 1, 7})
+	checkImplicitTreap(right, []int{6, 5, 4, 3, 2, 8, 9})
+	right.Reverse(0, right.Len())
+	right.Merge(left)
+	checkImplicitTreap(right, []int{9, 8, 2, 3, 4, 5, 6, 10, 1, 7})
+	checkImplicitTreap(left, []int{})
+	func() {
+		defer func() {
+			if recover() == nil {
+				panic("Expect Merge to panic on merging with itself")
+			}
+		}()
+		right.Merge(right)
+	}()
+	checkImplicitTreap(right, []int{9, 8, 2, 3, 4, 5, 6, 10, 1, 7})
+
+	// Validate leaves pending reversals in place
+	countReversed := func(t *ImplicitTreap[int]) int {
+		count := 0
+		var walk func(node *implicitTreapNode[int])
+		walk = func(node *implicitTreapNode[int]) {
+			if node != nil {
+				if node.reversed {
+					count++
+				}
+				walk(node.Left)
+				walk(node.Right)
+			}
+		}
+		walk(t.root)
+		return count
+	}
+	right.Reverse(1, 9)
+	pending := countReversed(right)
+	if err := right.Validate(); err != nil {
+		panic(err)
+	}
+	if pending == 0 || countReversed(right) != pending {
+		panic(fmt.Sprintf("Expect Validate to keep %d pending reversals, but got %d", pending, countReversed(right)))
+	}
+	checkImplicitTreap(right, []int{9, 1, 10, 6, 5, 4, 3, 2, 8, 7})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				panic("Expect Get to panic on index out of range")
+			}
+		}()
+		right.Get(right.Len())
+	}()
+
+	right.Clear()
+	checkImplicitTreap(right, []int{})
+}
@@ -0,0 +1,185 @@
+package container
+
+import "fmt"
+
+// TreeMultiMap is a sorted map allowing duplicated keys, where values
+// of the same key are kept in their insertion order. It is backed by AVLTree.
+type TreeMultiMap[K, V any] struct {
+	tree *AVLTree[K, []V]
+	size int
+}
+
+// NewTreeMultiMap returns a new TreeMultiMap object given a comparator of key type
+func NewTreeMultiMap[K, V any](cmp func(K,K) int) *TreeMultiMap[K,V] {
+	return &TreeMultiMap[K,V]{
+		tree: NewAVLTree[K, []V](cmp),
+	}
+}
+
+// Insert a key-value pair into the TreeMultiMap, the value is placed
+// after the existing values of the same key
+func (m *TreeMultiMap[K,V]) Insert(k K, v V) {
+	values, _ := m.tree.Get(k)
+	m.tree.Insert(k, append(values, v))
+	m.size++
+}
+
+// Has the given key in the TreeMultiMap
+func (m *TreeMultiMap[K,V]) Has(k K) bool {
+	return m.tree.Has(k)
+}
+
+// Count returns the number of values of the given key
+func (m *TreeMultiMap[K,V]) Count(k K) int {
+	values, _ := m.tree.Get(k)
+	return len(values)
+}
+
+// GetAll returns a copy of values of the given key in insertion order,
+// it returns an empty slice if the key doesn't exist
+func (m *TreeMultiMap[K,V]) GetAll(k K) []V {
+	values, _ := m.tree.Get(k)
+	return append([]V{}, values...)
+}
+
+// RemoveOne removes the earliest inserted value of the given key,
+// returns the value and if the key exists.
+// If the key doesn't exist, returned value is the zero-value of V
+func (m *TreeMultiMap[K,V]) RemoveOne(k K) (v V, ok bool) {
+	values, ok := m.tree.Get(k)
+	if !ok {
+		return
+	}
+	v = values[0]
+	if len(values) == 1 {
+		m.tree.Remove(k)
+	} else {
+		var zero V
+		values[0] = zero // avoid memory leak
+		m.tree.Insert(k, values[1:])
+	}
+	m.size--
+	return
+}
+
+// RemoveAll removes all values of the given key, returns the number of removed values
+func (m *TreeMultiMap[K,V]) RemoveAll(k K) int {
+	values, _ := m.tree.Get(k)
+	m.tree.Remove(k)
+	m.size -= len(values)
+	return len(values)
+}
+
+// Range calls fn on each key-value pair with key between lo and hi in ascending order,
+// values of the same key are visited in insertion order. Whether lo and hi are
+// included is decided by loInclusive and hiInclusive. It stops once fn returns false.
+func (m *TreeMultiMap[K,V]) Range(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool) {
+	m.tree.Range(lo, hi, loInclusive, hiInclusive, func(k K, values []V) bool {
+		for _, v := range values {
+			if !fn(k, v) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// RangeReverse is the same as Range but visits key-value pairs in exactly the reversed order
+func (m *TreeMultiMap[K,V]) RangeReverse(lo, hi K, loInclusive, hiInclusive bool, fn func(K,V) bool) {
+	m.tree.RangeReverse(lo, hi, loInclusive, hiInclusive, func(k K, values []V) bool {
+		for i := len(values) - 1; i >= 0; i-- {
+			if !fn(k, values[i]) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Clear all elements in the TreeMultiMap
+func (m *TreeMultiMap[K,V]) Clear() {
+	m.tree.Clear()
+	m.size = 0
+}
+
+// Len returns the total number of values in the TreeMultiMap
+func (m *TreeMultiMap[K,V]) Len() int {
+	return m.size
+}
+
+/////////////////////////////
+///////// Testing ///////////
+/////////////////////////////
+
+func checkMultiMapValues(got []string, expect []string) {
+	if len(got) != len(expect) {
+		panic(fmt.Sprintf("Expect values %v, but got %v", expect, got))
+	}
+	for i := range expect {
+		if got[i] != expect[i] {
+			panic(fmt.Sprintf("Expect values %v, but got %v", expect, got))
+		}
+	}
+}
+
+func testTreeMultiMap() {
+	m := NewTreeMultiMap[int, string](CmpLess[int])
+	checkAVLTreeNum(m.Len(), 0)
+	checkAVLTreeNum(m.Count(1), 0)
+	checkMultiMapValues(m.GetAll(1), []string{})
+	_, ok := m.RemoveOne(1)
+	if ok {
+		panic("Expect RemoveOne on missing key to fail")
+	}
+
+	m.Insert(2, "b1")
+	m.Insert(1, "a1")
+	m.Insert(2, "b2")
+	m.Insert(3, "c1")
+	m.Insert(2, "b3")
+	checkAVLTreeNum(m.Len(), 5)
+	checkAVLTreeNum(m.Count(2), 3)
+	checkMultiMapValues(m.GetAll(2), []string{"b1", "b2", "b3"})
+
+	got := []string{}
+	m.Range(1, 3, true, false, func(k int, v string) bool {
+		got = append(got, v)
+		return true
+	})
+	checkMultiMapValues(got, []string{"a1", "b1", "b2", "b3"})
+	got = got[:0]
+	m.RangeReverse(1, 3, false, true, func(k int, v string) bool {
+		got = append(got, v)
+		return true
+	})
+	checkMultiMapValues(got, []string{"c1", "b3", "b2", "b1"})
+	got = got[:0]
+	m.Range(1, 3, true, true, func(k int, v string) bool {
+		got = append(got, v)
+		return v != "b2"
+	})
+	checkMultiMapValues(got, []string{"a1", "b1", "b2"})
+
+	v, ok := m.RemoveOne(2)
+	if !ok || v != "b1" {
+		panic(fmt.Sprintf("Expect RemoveOne to return b1, but got %v,%t", v, ok))
+	}
+	checkMultiMapValues(m.GetAll(2), []string{"b2", "b3"})
+	checkAVLTreeNum(m.Len(), 4)
+	m.Insert(2, "b4")
+	checkMultiMapValues(m.GetAll(2), []string{"b2", "b3", "b4"})
+	checkAVLTreeNum(m.RemoveAll(2), 3)
+	checkAVLTreeNum(m.RemoveAll(2), 0)
+	checkAVLTreeNum(m.Len(), 2)
+	if m.Has(2) || !m.Has(1) {
+		panic("Has check failed")
+	}
+	v, ok = m.RemoveOne(1)
+	if !ok || v != "a1" || m.Has(1) {
+		panic(fmt.Sprintf("Expect RemoveOne to return a1 and remove key, but got %v,%t", v, ok))
+	}
+
+	m.Clear()
+	checkAVLTreeNum(m.Len(), 0)
+	checkAVLTreeNum(m.Count(3), 0)
+}
@@ -61,7 +61,7 @@ func (v Vector[T]) Reverse() {
 	}
 }
 
-// Index the given element. If the element doesn't exist, return -1
+// Index the given element using reflect.DeepEqual. If the element doesn't exist, return -1.
 func (v Vector[T]) Index(x T) int {
 	for i, e := range v {
 		if reflect.DeepEqual(e, x) {
@@ -71,6 +71,27 @@ func (v Vector[T]) Index(x T) int {
 	return -1
 }
 
+// IndexFunc returns the index of the first element satisfying pred. If no such element, return -1
+func (v Vector[T]) IndexFunc(pred func(T) bool) int {
+	for i, e := range v {
+		if pred(e) {
+			return i
+		}
+	}
+	return -1
+}
+
+// VectorIndex returns the index of the first element of v equal to x using ==.
+// If the element doesn't exist, return -1
+func VectorIndex[T comparable](v Vector[T], x T) int {
+	for i, e := range v {
+		if e == x {
+			return i
+		}
+	}
+	return -1
+}
+
 /////////////////////////////
 ///////// Testing ///////////
 /////////////////////////////
@@ -114,4 +135,12 @@ func testVector() {
 	checkElement(index, 2)
 	index = v.Index(2)
 	checkElement(index, -1)
+	index = v.IndexFunc(func(x int) bool { return x < 4 })
+	checkElement(index, 0)
+	index = v.IndexFunc(func(x int) bool { return x > 4 })
+	checkElement(index, -1)
+	index = VectorIndex[int](*v, 4)
+	checkElement(index, 1)
+	index = VectorIndex[int](*v, 2)
+	checkElement(index, -1)
 }